@@ -0,0 +1,116 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Axis returns the declared variant axis with the given name.
+func (m Manifest) Axis(name string) (VariantAxis, bool) {
+	for _, axis := range m.Axes {
+		if axis.Name == name {
+			return axis, true
+		}
+	}
+	return VariantAxis{}, false
+}
+
+// AxisVariants orders the variants picked per axis (axis name → value) by the manifest's axis order,
+// the precedence used when merging them. Unset axes, unknown axes and unknown values are skipped.
+func (m Manifest) AxisVariants(values map[string]string) []string {
+	var variants []string
+	for _, axis := range m.Axes {
+		if value := values[axis.Name]; value != "" && containsString(axis.Values, value) {
+			variants = append(variants, value)
+		}
+	}
+	return variants
+}
+
+// AxisSelector is implemented by selectors that pick one variant per declared axis. Negotiator uses it
+// for themes that declare axes.
+type AxisSelector interface {
+	SelectAxes(themeName string, axes map[string]string, opts ...QueryOption) (*Selection, error)
+}
+
+// SelectAxes resolves a theme like Select but picks one variant per declared axis instead of a single
+// variant. Unset axes use the axis default; unknown axes are ignored and unknown values fall back to the
+// axis default (or no override), each reported in Selection.Fallbacks. With StrictVariants set, unknown
+// axes and values fail with ErrVariantNotFound.
+func (s Selector) SelectAxes(themeName string, axes map[string]string, opts ...QueryOption) (*Selection, error) {
+	settings := newQueryOptions(opts)
+	requested := newSelectionRequest(themeName, settings)
+	requestedAxes := make(map[string]string, len(axes))
+	for name, value := range axes {
+		requestedAxes[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	manifest, fallbacks, err := s.resolveTheme(requested, settings, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, axisFallbacks, err := s.resolveAxes(manifest, requestedAxes)
+	if err != nil {
+		return nil, err
+	}
+	fallbacks = append(fallbacks, axisFallbacks...)
+
+	return &Selection{
+		Theme:         manifest.Name,
+		Axes:          resolved,
+		Version:       manifest.Version,
+		Requested:     requested,
+		RequestedAxes: requestedAxes,
+		Fallbacks:     fallbacks,
+		Manifest:      manifest,
+	}, nil
+}
+
+// resolveAxes maps requested axis values onto the manifest's axes.
+func (s Selector) resolveAxes(manifest *Manifest, requested map[string]string) (map[string]string, []SelectionFallback, error) {
+	var fallbacks []SelectionFallback
+	for _, name := range sortedKeys(requested) {
+		if _, ok := manifest.Axis(name); ok || requested[name] == "" {
+			continue
+		}
+		if s.StrictVariants {
+			return nil, nil, fmt.Errorf("%w: theme '%s' has no variant axis '%s'", ErrVariantNotFound, manifest.Name, name)
+		}
+		fallbacks = append(fallbacks, SelectionFallback{
+			Code:    FallbackReasonAxisNotFound,
+			Message: fmt.Sprintf("theme '%s' has no variant axis '%s', ignoring '%s'", manifest.Name, name, requested[name]),
+		})
+	}
+
+	resolved := make(map[string]string, len(manifest.Axes))
+	for _, axis := range manifest.Axes {
+		value := requested[axis.Name]
+		switch {
+		case value != "" && containsString(axis.Values, value):
+			resolved[axis.Name] = value
+		case value == "":
+			if axis.Default != "" {
+				resolved[axis.Name] = axis.Default
+				fallbacks = append(fallbacks, SelectionFallback{
+					Code:    FallbackReasonDefaultVariant,
+					Message: fmt.Sprintf("no value requested for axis '%s', using default '%s'", axis.Name, axis.Default),
+				})
+			}
+		case s.StrictVariants:
+			return nil, nil, fmt.Errorf("%w: theme '%s' axis '%s' has no value '%s'", ErrVariantNotFound, manifest.Name, axis.Name, value)
+		case axis.Default != "":
+			resolved[axis.Name] = axis.Default
+			fallbacks = append(fallbacks, SelectionFallback{
+				Code:    FallbackReasonVariantNotFound,
+				Message: fmt.Sprintf("theme '%s' axis '%s' has no value '%s', using default '%s'", manifest.Name, axis.Name, value, axis.Default),
+			})
+		default:
+			fallbacks = append(fallbacks, SelectionFallback{
+				Code:    FallbackReasonVariantNotFound,
+				Message: fmt.Sprintf("theme '%s' axis '%s' has no value '%s', using base values", manifest.Name, axis.Name, value),
+			})
+		}
+	}
+	return resolved, fallbacks, nil
+}
@@ -0,0 +1,151 @@
+package theme
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func axesManifest() *Manifest {
+	return &Manifest{
+		Name:      "default",
+		Version:   "1.0.0",
+		Tokens:    map[string]string{"bg": "white", "fg": "black", "space": "8px", "ring": "{space} solid {fg}"},
+		Templates: map[string]string{"layout": "base/layout.tmpl"},
+		Assets:    Assets{Prefix: "/static", Files: map[string]string{"logo": "logo.svg"}},
+		Variants: map[string]Variant{
+			"light":         {},
+			"dark":          {Tokens: map[string]string{"bg": "black", "fg": "white"}, Assets: Assets{Files: map[string]string{"logo": "logo-dark.svg"}}},
+			"compact":       {Tokens: map[string]string{"space": "4px"}, Templates: map[string]string{"layout": "compact/layout.tmpl"}},
+			"comfortable":   {},
+			"high-contrast": {Tokens: map[string]string{"fg": "yellow"}, Templates: map[string]string{"layout": "hc/layout.tmpl"}},
+		},
+		Axes: []VariantAxis{
+			{Name: "scheme", Values: []string{"light", "dark"}, Default: "light"},
+			{Name: "density", Values: []string{"compact", "comfortable"}, Default: "comfortable"},
+			{Name: "contrast", Values: []string{"high-contrast"}},
+		},
+	}
+}
+
+func TestSelectAxesMergesInAxisOrder(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(axesManifest()); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	selector := Selector{Registry: reg}
+
+	sel, err := selector.SelectAxes("default", map[string]string{"contrast": "high-contrast", "scheme": "dark", "density": "compact"})
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+
+	snapshot := sel.Snapshot()
+	if snapshot.Tokens["bg"] != "black" || snapshot.Tokens["fg"] != "yellow" || snapshot.Tokens["ring"] != "4px solid yellow" {
+		t.Fatalf("expected later axes to win, got %v", snapshot.Tokens)
+	}
+	if snapshot.Templates["layout"] != "hc/layout.tmpl" {
+		t.Fatalf("expected contrast template to win over density, got %s", snapshot.Templates["layout"])
+	}
+	if snapshot.Assets["logo"] != "/static/logo-dark.svg" {
+		t.Fatalf("expected scheme asset override, got %s", snapshot.Assets["logo"])
+	}
+	if snapshot.Axes["scheme"] != "dark" || snapshot.Axes["density"] != "compact" || len(sel.Fallbacks) != 0 {
+		t.Fatalf("unexpected axes %v, fallbacks %+v", snapshot.Axes, sel.Fallbacks)
+	}
+}
+
+func TestSelectAxesFallbacks(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(axesManifest()); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	sel, err := Selector{Registry: reg}.SelectAxes("default", map[string]string{"scheme": "sepia", "motion": "reduced"})
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if sel.Axes["scheme"] != "light" || sel.Axes["density"] != "comfortable" {
+		t.Fatalf("expected axis defaults, got %v", sel.Axes)
+	}
+	if _, ok := sel.Axes["contrast"]; ok {
+		t.Fatalf("expected contrast axis without default to stay unset, got %v", sel.Axes)
+	}
+	var codes []string
+	for _, fallback := range sel.Fallbacks {
+		codes = append(codes, fallback.Code)
+	}
+	if got := strings.Join(codes, ","); got != "axis_not_found,variant_not_found,default_variant" {
+		t.Fatalf("unexpected fallbacks: %s", got)
+	}
+	if sel.RequestedAxes["scheme"] != "sepia" {
+		t.Fatalf("expected requested axes to be kept, got %v", sel.RequestedAxes)
+	}
+
+	strict := Selector{Registry: reg, StrictVariants: true}
+	if _, err := strict.SelectAxes("default", map[string]string{"scheme": "sepia"}); !errors.Is(err, ErrVariantNotFound) {
+		t.Fatalf("expected ErrVariantNotFound for unknown value, got %v", err)
+	}
+	if _, err := strict.SelectAxes("default", map[string]string{"motion": "reduced"}); !errors.Is(err, ErrVariantNotFound) {
+		t.Fatalf("expected ErrVariantNotFound for unknown axis, got %v", err)
+	}
+}
+
+func TestValidateAxes(t *testing.T) {
+	m := axesManifest()
+	m.Axes = append(m.Axes,
+		VariantAxis{Name: "scheme", Values: []string{"dark"}},
+		VariantAxis{Name: "motion", Values: []string{"reduced"}, Default: "full"},
+	)
+
+	var got []string
+	for _, issue := range m.ValidationIssues() {
+		got = append(got, issue.Path+":"+issue.Code)
+	}
+	want := "axes.3.name:invalid_value,axes.3.values.0:invalid_value,axes.4.default:unknown_variant,axes.4.values.0:unknown_variant"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected issues: %v", got)
+	}
+}
+
+func TestStylesheetAxisCombinations(t *testing.T) {
+	m := axesManifest()
+	m.Axes = m.Axes[:2]
+
+	css := m.Stylesheet(StylesheetOptions{AxisSelectors: map[string]string{"scheme": ".theme-%s"}})
+	expected := `:root {
+  --bg: white;
+  --fg: black;
+  --ring: 8px solid black;
+  --space: 8px;
+}
+
+[data-theme="high-contrast"] {
+  --fg: yellow;
+  --ring: 8px solid yellow;
+}
+
+.theme-dark {
+  --bg: black;
+  --fg: white;
+  --ring: 8px solid white;
+}
+
+[data-density="compact"] {
+  --ring: 4px solid black;
+  --space: 4px;
+}
+
+.theme-dark[data-density="compact"] {
+  --ring: 4px solid white;
+}
+`
+	if css != expected {
+		t.Fatalf("unexpected stylesheet:\n%s", css)
+	}
+
+	css = m.Stylesheet(StylesheetOptions{AxisSelectors: map[string]string{"scheme": VariantSelectorMedia}, Minify: true})
+	if !strings.HasSuffix(css, `@media (prefers-color-scheme: dark){[data-density="compact"]{--ring:4px solid white}}`) {
+		t.Fatalf("expected nested combined rule, got %s", css)
+	}
+}
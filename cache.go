@@ -0,0 +1,261 @@
+package theme
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CacheOption configures a CachingProvider.
+type CacheOption func(*CachingProvider)
+
+// WithCacheTTL sets how long resolved manifests and listings stay cached (default one minute);
+// zero keeps them until evicted or invalidated.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(c *CachingProvider) {
+		c.ttl = ttl
+	}
+}
+
+// WithCacheSize bounds the number of cached lookups; the least recently used entry is evicted
+// first (default 256).
+func WithCacheSize(size int) CacheOption {
+	return func(c *CachingProvider) {
+		if size > 0 {
+			c.size = size
+		}
+	}
+}
+
+// WithNegativeTTL caches ErrThemeNotFound results for ttl (default: the positive TTL); a negative
+// value disables negative caching.
+func WithNegativeTTL(ttl time.Duration) CacheOption {
+	return func(c *CachingProvider) {
+		c.negativeTTL = &ttl
+	}
+}
+
+// CachingProvider decorates a ThemeProvider with an LRU cache. Concurrent misses for the same lookup
+// share a single call to the wrapped provider. Returned manifests are copies, safe to modify.
+type CachingProvider struct {
+	provider    ThemeProvider
+	ttl         time.Duration
+	negativeTTL *time.Duration
+	size        int
+	now         func() time.Time
+
+	mu       sync.Mutex
+	entries  map[cacheKey]*list.Element
+	lru      *list.List
+	inflight map[cacheKey]*cacheCall
+	listing  *cachedListing
+	// generation increases on every invalidation so results fetched before it are not cached.
+	generation uint64
+}
+
+type cacheKey struct {
+	name       string
+	version    string
+	constraint string
+	fallback   FallbackPolicy
+	raw        bool
+}
+
+type cacheEntry struct {
+	key      cacheKey
+	manifest *Manifest
+	err      error
+	expires  time.Time
+}
+
+type cacheCall struct {
+	done     chan struct{}
+	manifest *Manifest
+	err      error
+	// waiters counts the callers sharing this call besides the leader.
+	waiters int
+}
+
+type cachedListing struct {
+	refs    []ManifestRef
+	expires time.Time
+}
+
+// NewCachingProvider wraps provider with a cache.
+func NewCachingProvider(provider ThemeProvider, opts ...CacheOption) *CachingProvider {
+	c := &CachingProvider{
+		provider: provider,
+		ttl:      time.Minute,
+		size:     256,
+		now:      time.Now,
+		entries:  make(map[cacheKey]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[cacheKey]*cacheCall),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Theme returns a cached manifest or resolves it through the wrapped provider.
+func (c *CachingProvider) Theme(name string, opts ...QueryOption) (*Manifest, error) {
+	settings := newQueryOptions(opts)
+	key := cacheKey{name: name, version: settings.version, constraint: settings.constraint, fallback: settings.fallback, raw: settings.raw}
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if c.fresh(entry.expires) {
+			c.lru.MoveToFront(elem)
+			c.mu.Unlock()
+			return copyManifest(entry.manifest), entry.err
+		}
+		c.removeElement(elem)
+	}
+	if call, ok := c.inflight[key]; ok {
+		call.waiters++
+		c.mu.Unlock()
+		<-call.done
+		return copyManifest(call.manifest), call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.inflight[key] = call
+	generation := c.generation
+	c.mu.Unlock()
+
+	c.resolve(key, call, generation, name, opts)
+	return copyManifest(call.manifest), call.err
+}
+
+// resolve fetches key from the wrapped provider as the single-flight leader. The call always completes
+// and leaves inflight, so waiters are released even when the provider panics; they receive an error
+// and the panic is re-raised in the leader.
+func (c *CachingProvider) resolve(key cacheKey, call *cacheCall, generation uint64, name string, opts []QueryOption) {
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			call.manifest, call.err = nil, fmt.Errorf("theme provider panicked: %v", recovered)
+		}
+
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if recovered == nil && generation == c.generation {
+			c.store(key, call.manifest, call.err)
+		}
+		c.mu.Unlock()
+		close(call.done)
+
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+	call.manifest, call.err = c.provider.Theme(name, opts...)
+}
+
+// Themes returns the cached listing or fetches it from the wrapped provider.
+func (c *CachingProvider) Themes() []ManifestRef {
+	c.mu.Lock()
+	if c.listing != nil && c.fresh(c.listing.expires) {
+		refs := append([]ManifestRef(nil), c.listing.refs...)
+		c.mu.Unlock()
+		return refs
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	refs := c.provider.Themes()
+
+	c.mu.Lock()
+	if generation == c.generation {
+		c.listing = &cachedListing{refs: append([]ManifestRef(nil), refs...), expires: c.expiry(c.ttl)}
+	}
+	c.mu.Unlock()
+	return refs
+}
+
+// ThemesFiltered filters the cached listing.
+func (c *CachingProvider) ThemesFiltered(filter ListFilter) ManifestPage {
+	return filterManifestRefs(c.Themes(), filter)
+}
+
+// Invalidate drops every cached lookup for a theme name, plus the cached listing.
+func (c *CachingProvider) Invalidate(name string) {
+	c.invalidate(func(entry *cacheEntry) bool {
+		return entry.key.name == name
+	})
+}
+
+// InvalidateVersion drops cached lookups for name that requested or resolved to version, plus negative
+// results for name and the cached listing. Latest/constraint lookups resolved to another version are
+// kept; use Invalidate when a newly registered version may change them.
+func (c *CachingProvider) InvalidateVersion(name, version string) {
+	c.invalidate(func(entry *cacheEntry) bool {
+		if entry.key.name != name {
+			return false
+		}
+		return entry.err != nil || entry.key.version == version || (entry.manifest != nil && entry.manifest.Version == version)
+	})
+}
+
+// InvalidateAll empties the cache.
+func (c *CachingProvider) InvalidateAll() {
+	c.invalidate(func(*cacheEntry) bool { return true })
+}
+
+func (c *CachingProvider) invalidate(match func(*cacheEntry) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*cacheEntry)) {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+	c.listing = nil
+	c.generation++
+}
+
+// store caches a lookup result when it is cacheable. Callers must hold c.mu.
+func (c *CachingProvider) store(key cacheKey, manifest *Manifest, err error) {
+	ttl := c.ttl
+	if err != nil {
+		if !isThemeMissing(err) {
+			return
+		}
+		if c.negativeTTL != nil {
+			ttl = *c.negativeTTL
+		}
+		if ttl < 0 {
+			return
+		}
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, manifest: manifest, err: err, expires: c.expiry(ttl)})
+	for c.lru.Len() > c.size {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// removeElement drops an LRU element. Callers must hold c.mu.
+func (c *CachingProvider) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+func (c *CachingProvider) expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return c.now().Add(ttl)
+}
+
+func (c *CachingProvider) fresh(expires time.Time) bool {
+	return expires.IsZero() || c.now().Before(expires)
+}
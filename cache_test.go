@@ -0,0 +1,226 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingProvider struct {
+	ThemeProvider
+	calls   atomic.Int32
+	lists   atomic.Int32
+	release chan struct{}
+}
+
+func (p *countingProvider) Theme(name string, opts ...QueryOption) (*Manifest, error) {
+	p.calls.Add(1)
+	if p.release != nil {
+		<-p.release
+	}
+	return p.ThemeProvider.Theme(name, opts...)
+}
+
+func (p *countingProvider) Themes() []ManifestRef {
+	p.lists.Add(1)
+	return p.ThemeProvider.Themes()
+}
+
+func TestCachingProviderTTLAndInvalidation(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "acme", Version: "1.0.0", Tokens: map[string]string{"primary": "blue"}})
+	backend := &countingProvider{ThemeProvider: reg}
+
+	now := time.Unix(0, 0)
+	cache := NewCachingProvider(backend, WithCacheTTL(time.Minute))
+	cache.now = func() time.Time { return now }
+
+	first, _ := cache.Theme("acme")
+	first.Tokens["primary"] = "mutated"
+	second, _ := cache.Theme("acme")
+	if backend.calls.Load() != 1 || second.Tokens["primary"] != "blue" {
+		t.Fatalf("expected one backend call and an unmodified copy, got %d calls, %+v", backend.calls.Load(), second.Tokens)
+	}
+	cache.Theme("acme", WithVersion("1.0.0"))
+	if backend.calls.Load() != 2 {
+		t.Fatalf("expected query options to be part of the cache key, got %d calls", backend.calls.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	cache.Theme("acme")
+	if backend.calls.Load() != 3 {
+		t.Fatalf("expected expired entry to be refetched, got %d calls", backend.calls.Load())
+	}
+
+	reg.Register(&Manifest{Name: "acme", Version: "1.1.0"})
+	if m, _ := cache.Theme("acme"); m.Version != "1.0.0" {
+		t.Fatalf("expected cached version before invalidation, got %s", m.Version)
+	}
+	cache.Invalidate("acme")
+	if m, _ := cache.Theme("acme"); m.Version != "1.1.0" {
+		t.Fatalf("expected new latest after invalidation, got %s", m.Version)
+	}
+
+	cache.Theme("acme", WithVersion("1.0.0"))
+	calls := backend.calls.Load()
+	cache.InvalidateVersion("acme", "1.0.0")
+	cache.Theme("acme", WithVersion("1.0.0"))
+	cache.Theme("acme")
+	if backend.calls.Load() != calls+1 {
+		t.Fatalf("expected only the 1.0.0 entry to be invalidated, got %d extra calls", backend.calls.Load()-calls)
+	}
+
+	cache.Themes()
+	cache.Themes()
+	if backend.lists.Load() != 1 {
+		t.Fatalf("expected cached listing, got %d calls", backend.lists.Load())
+	}
+	cache.InvalidateAll()
+	cache.Themes()
+	if backend.lists.Load() != 2 {
+		t.Fatalf("expected listing to be refetched after InvalidateAll, got %d calls", backend.lists.Load())
+	}
+}
+
+func TestCachingProviderNegativeCachingAndLRU(t *testing.T) {
+	reg := NewRegistry()
+	backend := &countingProvider{ThemeProvider: reg}
+	cache := NewCachingProvider(backend, WithCacheSize(2))
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Theme("missing"); !errors.Is(err, ErrThemeNotFound) {
+			t.Fatalf("expected ErrThemeNotFound, got %v", err)
+		}
+	}
+	if backend.calls.Load() != 1 {
+		t.Fatalf("expected negative result to be cached, got %d calls", backend.calls.Load())
+	}
+
+	disabled := NewCachingProvider(backend, WithNegativeTTL(-1))
+	disabled.Theme("missing")
+	disabled.Theme("missing")
+	if backend.calls.Load() != 3 {
+		t.Fatalf("expected negative caching to be disabled, got %d calls", backend.calls.Load())
+	}
+
+	for _, name := range []string{"a", "b", "c"} {
+		reg.Register(&Manifest{Name: name, Version: "1.0.0"})
+	}
+	cache.InvalidateAll()
+	backend.calls.Store(0)
+	cache.Theme("a")
+	cache.Theme("b")
+	cache.Theme("a")
+	cache.Theme("c") // evicts b, the least recently used
+	cache.Theme("a")
+	cache.Theme("b")
+	if backend.calls.Load() != 4 {
+		t.Fatalf("expected b to be evicted, got %d calls", backend.calls.Load())
+	}
+}
+
+func TestCachingProviderSingleFlight(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "acme", Version: "1.0.0"})
+	backend := &countingProvider{ThemeProvider: reg, release: make(chan struct{})}
+	cache := NewCachingProvider(backend)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if m, err := cache.Theme("acme"); err != nil || m.Name != "acme" {
+				t.Errorf("unexpected result %+v (%v)", m, err)
+			}
+		}()
+	}
+
+	for backend.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(backend.release)
+	wg.Wait()
+
+	if backend.calls.Load() != 1 {
+		t.Fatalf("expected concurrent misses to share one call, got %d", backend.calls.Load())
+	}
+}
+
+type panickingProvider struct {
+	ThemeProvider
+	panics  atomic.Bool
+	release chan struct{}
+}
+
+func (p *panickingProvider) Theme(name string, opts ...QueryOption) (*Manifest, error) {
+	if p.panics.Load() {
+		<-p.release
+		panic("provider failure")
+	}
+	return p.ThemeProvider.Theme(name, opts...)
+}
+
+func TestCachingProviderSingleFlightSurvivesPanic(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "acme", Version: "1.0.0"})
+	backend := &panickingProvider{ThemeProvider: reg, release: make(chan struct{})}
+	backend.panics.Store(true)
+	cache := NewCachingProvider(backend)
+
+	leader := make(chan any, 1)
+	go func() {
+		defer func() { leader <- recover() }()
+		cache.Theme("acme")
+	}()
+	for {
+		cache.mu.Lock()
+		started := len(cache.inflight) == 1
+		cache.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				waiter <- fmt.Errorf("waiter became the leader: %v", recovered)
+			}
+		}()
+		_, err := cache.Theme("acme")
+		waiter <- err
+	}()
+	for {
+		cache.mu.Lock()
+		waiting := 0
+		for _, call := range cache.inflight {
+			waiting = call.waiters
+		}
+		cache.mu.Unlock()
+		if waiting == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(backend.release)
+
+	if recovered := <-leader; recovered != "provider failure" {
+		t.Fatalf("expected the panic to be re-raised in the leader, got %v", recovered)
+	}
+	if err := <-waiter; err == nil || !strings.Contains(err.Error(), "theme provider panicked: provider failure") {
+		t.Fatalf("expected the waiter to receive the single-flight panic error, got %v", err)
+	}
+
+	backend.panics.Store(false)
+	if m, err := cache.Theme("acme"); err != nil || m.Name != "acme" {
+		t.Fatalf("expected a later lookup to reach the provider again, got %+v (%v)", m, err)
+	}
+}
@@ -0,0 +1,243 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidConstraint is returned when a version constraint cannot be parsed.
+var ErrInvalidConstraint = errors.New("invalid version constraint")
+
+// versionConstraint is a parsed constraint: comparators within a group are ANDed, groups are ORed.
+type versionConstraint struct {
+	raw    string
+	groups [][]versionComparator
+}
+
+type versionComparator struct {
+	op      string
+	version semVersion
+}
+
+// constraintOperators are the term prefixes; a space may separate one from its version (">= 1.0").
+var constraintOperators = map[string]bool{">=": true, "<=": true, ">": true, "<": true, "=": true, "^": true, "~": true}
+
+// parseConstraint parses constraints such as "^1.2", "~1.4.0", ">=1.0 <2.0", "1.x" or "^1 || ^2".
+// Partial versions are completed with zeros; "1.2" and "1.2.x" match any 1.2 patch release. An operator
+// may be separated from its version by spaces (">= 1.0").
+func parseConstraint(raw string) (versionConstraint, error) {
+	c := versionConstraint{raw: strings.TrimSpace(raw)}
+	if c.raw == "" {
+		return c, fmt.Errorf("%w: empty constraint", ErrInvalidConstraint)
+	}
+
+	for _, group := range strings.Split(c.raw, "||") {
+		var comparators []versionComparator
+		terms := strings.Fields(strings.ReplaceAll(group, ",", " "))
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			if constraintOperators[term] {
+				if i+1 == len(terms) {
+					return c, fmt.Errorf("%w: %q: operator %q has no version", ErrInvalidConstraint, c.raw, term)
+				}
+				i++
+				term += terms[i]
+			}
+			expanded, err := expandConstraintTerm(term)
+			if err != nil {
+				return c, fmt.Errorf("%w: %q: %v", ErrInvalidConstraint, c.raw, err)
+			}
+			comparators = append(comparators, expanded...)
+		}
+		if len(comparators) == 0 {
+			return c, fmt.Errorf("%w: %q has an empty range", ErrInvalidConstraint, c.raw)
+		}
+		c.groups = append(c.groups, comparators)
+	}
+	return c, nil
+}
+
+// expandConstraintTerm turns a single term into primitive comparators (=, >, >=, <, <=).
+func expandConstraintTerm(term string) ([]versionComparator, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			term = strings.TrimSpace(term[len(candidate):])
+			break
+		}
+	}
+
+	version, parts, err := parsePartialVersion(term)
+	if err != nil {
+		return nil, err
+	}
+	if parts == 0 {
+		if op == "" || op == "=" || op == ">=" || op == "<=" {
+			return []versionComparator{{op: ">=", version: semVersion{}}}, nil
+		}
+		return nil, fmt.Errorf("wildcard cannot be used with %q", op)
+	}
+
+	switch op {
+	case "^":
+		upper := semVersion{major: version.major + 1}
+		switch {
+		case version.major == 0 && parts >= 2 && version.minor > 0:
+			upper = semVersion{minor: version.minor + 1}
+		case version.major == 0 && parts == 3:
+			upper = semVersion{minor: version.minor, patch: version.patch + 1}
+		case version.major == 0 && parts == 2:
+			upper = semVersion{minor: version.minor + 1}
+		}
+		return rangeComparators(version, upper), nil
+	case "~":
+		if parts == 1 {
+			return rangeComparators(version, semVersion{major: version.major + 1}), nil
+		}
+		return rangeComparators(version, semVersion{major: version.major, minor: version.minor + 1}), nil
+	case "", "=":
+		if parts == 3 {
+			return []versionComparator{{op: "=", version: version}}, nil
+		}
+		return rangeComparators(version, nextPartial(version, parts)), nil
+	case ">":
+		if parts == 3 {
+			return []versionComparator{{op: ">", version: version}}, nil
+		}
+		return []versionComparator{{op: ">=", version: nextPartial(version, parts)}}, nil
+	case "<=":
+		if parts == 3 {
+			return []versionComparator{{op: "<=", version: version}}, nil
+		}
+		return []versionComparator{{op: "<", version: nextPartial(version, parts)}}, nil
+	default:
+		return []versionComparator{{op: op, version: version}}, nil
+	}
+}
+
+func rangeComparators(lower, upper semVersion) []versionComparator {
+	return []versionComparator{{op: ">=", version: lower}, {op: "<", version: upper}}
+}
+
+// nextPartial returns the lowest version above every version matching a partial with the given parts.
+func nextPartial(version semVersion, parts int) semVersion {
+	if parts == 1 {
+		return semVersion{major: version.major + 1}
+	}
+	return semVersion{major: version.major, minor: version.minor + 1}
+}
+
+// parsePartialVersion parses "1", "1.2", "1.2.x", "*" or a full SemVer, returning how many core parts
+// were given (0 for a bare wildcard).
+func parsePartialVersion(value string) (semVersion, int, error) {
+	value = strings.TrimPrefix(value, "v")
+	if value == "" || value == "*" || value == "x" || value == "X" {
+		return semVersion{}, 0, nil
+	}
+
+	core := value
+	if idx := strings.IndexAny(core, "-+"); idx >= 0 {
+		core = core[:idx]
+	}
+	var parts []string
+	for _, part := range strings.Split(core, ".") {
+		if part == "*" || part == "x" || part == "X" {
+			break
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 3 {
+		version, err := parseSemVer(value)
+		return version, 3, err
+	}
+	if core != value {
+		return semVersion{}, 0, fmt.Errorf("%q: pre-release requires a full version", value)
+	}
+	if len(parts) == 0 || len(parts) > 3 {
+		return semVersion{}, 0, fmt.Errorf("%q is not a version", value)
+	}
+
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		n, err := parseSemVerNumber(part)
+		if err != nil {
+			return semVersion{}, 0, err
+		}
+		numbers[i] = n
+	}
+	return semVersion{major: numbers[0], minor: numbers[1], patch: numbers[2]}, len(parts), nil
+}
+
+// matches reports whether version satisfies the constraint. Pre-releases only match a group that
+// names a pre-release of the same major.minor.patch, so "^1.2" never selects "2.0.0-beta".
+func (c versionConstraint) matches(version string) bool {
+	v, err := parseSemVer(version)
+	if err != nil {
+		return false
+	}
+	for _, group := range c.groups {
+		if groupMatches(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func groupMatches(group []versionComparator, v semVersion) bool {
+	for _, comparator := range group {
+		if !comparator.matches(v) {
+			return false
+		}
+	}
+	if !v.isPrerelease() {
+		return true
+	}
+	for _, comparator := range group {
+		cv := comparator.version
+		if cv.isPrerelease() && cv.major == v.major && cv.minor == v.minor && cv.patch == v.patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c versionComparator) matches(v semVersion) bool {
+	cmp := v.compare(c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// major returns the major version of the constraint's lower bound, used for major-scoped fallback.
+// Each group is bounded below by its tightest =, > or >= comparator (0.0.0 without one), and the
+// constraint by its lowest group, so "<2.0 >=1.0" yields 1 and "^2 || ^1" yields 1.
+func (c versionConstraint) major() uint64 {
+	var lowest semVersion
+	for i, group := range c.groups {
+		var bound semVersion
+		for _, comparator := range group {
+			switch comparator.op {
+			case "=", ">", ">=":
+				if comparator.version.compare(bound) > 0 {
+					bound = comparator.version
+				}
+			}
+		}
+		if i == 0 || bound.compare(lowest) < 0 {
+			lowest = bound
+		}
+	}
+	return lowest.major
+}
@@ -0,0 +1,101 @@
+package theme
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConstraintMatches(t *testing.T) {
+	cases := []struct {
+		constraint string
+		match      []string
+		reject     []string
+	}{
+		{"^1.2", []string{"1.2.0", "1.9.3"}, []string{"1.1.9", "2.0.0", "1.3.0-beta"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.4.0", []string{"1.4.0", "1.4.7"}, []string{"1.5.0", "1.3.9"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">=1.0 <2.0", []string{"1.0.0", "1.99.0"}, []string{"0.9.0", "2.0.0", "2.0.0-rc.1"}},
+		{">= 1.0 < 2.0", []string{"1.0.0", "1.5.0"}, []string{"0.9.0", "2.0.0"}},
+		{"^ 1.2 || ~ 3.1", []string{"1.5.0", "3.1.4"}, []string{"2.0.0", "3.2.0"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"1.x", []string{"1.0.0", "1.8.1"}, []string{"2.0.0"}},
+		{"1.2.3", []string{"1.2.3", "1.2.3+build.1"}, []string{"1.2.4"}},
+		{"^1 || ^3", []string{"1.5.0", "3.0.0"}, []string{"2.0.0"}},
+		{">=2.0.0-beta.2 <3", []string{"2.0.0-beta.3", "2.0.0", "2.1.0"}, []string{"2.0.0-beta.1", "2.1.0-alpha"}},
+		{"*", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-rc.1"}},
+	}
+
+	for _, tc := range cases {
+		c, err := parseConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.constraint, err)
+		}
+		for _, v := range tc.match {
+			if !c.matches(v) {
+				t.Fatalf("%s: expected %s to match", tc.constraint, v)
+			}
+		}
+		for _, v := range tc.reject {
+			if c.matches(v) {
+				t.Fatalf("%s: expected %s not to match", tc.constraint, v)
+			}
+		}
+	}
+
+	for _, invalid := range []string{"", "^", ">= ", "1.0 <", ">=abc", "1.2-beta", "~*", "1.2.3.4"} {
+		if _, err := parseConstraint(invalid); !errors.Is(err, ErrInvalidConstraint) {
+			t.Fatalf("expected ErrInvalidConstraint for %q, got %v", invalid, err)
+		}
+	}
+}
+
+func TestRegistryGetWithConstraintAndFallback(t *testing.T) {
+	reg := NewRegistry()
+	for _, version := range []string{"1.2.0", "1.4.2", "1.5.0", "2.0.0", "3.0.0"} {
+		reg.Register(&Manifest{Name: "acme", Version: version})
+	}
+
+	get := func(opts ...QueryOption) string {
+		t.Helper()
+		m, err := reg.Get("acme", opts...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return m.Version
+	}
+
+	if got := get(WithConstraint("^1.2")); got != "1.5.0" {
+		t.Fatalf("expected ^1.2 to resolve 1.5.0, got %s", got)
+	}
+	if got := get(WithConstraint("~1.4.0")); got != "1.4.2" {
+		t.Fatalf("expected ~1.4.0 to resolve 1.4.2, got %s", got)
+	}
+	if got := get(WithConstraint(">=1.0 <2.0")); got != "1.5.0" {
+		t.Fatalf("expected range to resolve 1.5.0, got %s", got)
+	}
+
+	if got := get(WithConstraint("~1.6")); got != "3.0.0" {
+		t.Fatalf("expected default fallback to latest, got %s", got)
+	}
+	if got := get(WithConstraint("~1.6"), WithFallback(FallbackLatestMajor)); got != "1.5.0" {
+		t.Fatalf("expected fallback within major 1, got %s", got)
+	}
+	if got := get(WithConstraint("<2.0 >=1.6"), WithFallback(FallbackLatestMajor)); got != "1.5.0" {
+		t.Fatalf("expected range fallback within the lower bound's major 1, got %s", got)
+	}
+	if got := get(WithVersion("2.1.0"), WithFallback(FallbackLatestMajor)); got != "2.0.0" {
+		t.Fatalf("expected exact miss to fall back within major 2, got %s", got)
+	}
+	if _, err := reg.Get("acme", WithConstraint("^4"), WithFallback(FallbackLatestMajor)); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound without versions in major 4, got %v", err)
+	}
+	if _, err := reg.Get("acme", WithConstraint("~1.6"), WithFallback(FallbackError)); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound, got %v", err)
+	}
+	if _, err := reg.Get("acme", WithConstraint(">=abc")); !errors.Is(err, ErrInvalidConstraint) {
+		t.Fatalf("expected ErrInvalidConstraint, got %v", err)
+	}
+}
@@ -0,0 +1,322 @@
+package theme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Common variant selector patterns for StylesheetOptions.VariantSelector; %s is replaced by the variant name.
+const (
+	VariantSelectorAttribute = `[data-theme="%s"]`
+	VariantSelectorClass     = ".theme-%s"
+	VariantSelectorMedia     = "@media (prefers-color-scheme: %s)"
+)
+
+// StylesheetOptions configures how a manifest is rendered as CSS custom properties.
+type StylesheetOptions struct {
+	// Prefix is prepended to token names (defaults to "--").
+	Prefix string
+	// VariantSelector is the selector pattern for variant blocks (defaults to VariantSelectorAttribute).
+	// Patterns starting with "@" are treated as at-rules wrapping a :root block.
+	VariantSelector string
+	// VariantSelectors overrides the selector pattern for specific variants.
+	VariantSelectors map[string]string
+	// AxisSelectors sets the selector pattern for the variants of a declared axis (defaults to
+	// `[data-<axis>="%s"]`). Plain selectors are concatenated into compound selectors for
+	// combinations; at-rule patterns are nested.
+	AxisSelectors map[string]string
+	// Minify drops indentation, newlines and optional whitespace.
+	Minify bool
+}
+
+// Stylesheet renders base tokens in a :root block followed by one block per variant holding the
+// tokens whose resolved value differs from the base. Variants on a declared axis use the axis selector
+// and are followed by compound blocks for combinations across axes. Output is sorted and deterministic.
+func (m Manifest) Stylesheet(opts StylesheetOptions) string {
+	prefix := opts.Prefix
+	if prefix == "" {
+		prefix = "--"
+	}
+
+	var b strings.Builder
+	base := m.TokensForVariant("")
+	writeCSSBlock(&b, ":root", prefix, base, opts.Minify)
+
+	axisOf := m.variantAxes()
+	for _, name := range sortedVariantNames(m.Variants) {
+		if _, ok := axisOf[name]; ok {
+			continue
+		}
+		diff := diffTokens(base, m.TokensForVariant(name))
+		if len(diff) == 0 {
+			continue
+		}
+		writeCSSRule(&b, opts.variantSelector(name, ""), prefix, diff, opts.Minify)
+	}
+
+	m.writeAxisRules(&b, base, prefix, opts)
+	return b.String()
+}
+
+// writeAxisRules renders a block per axis value, then a compound block per combination of values across
+// axes holding the tokens the blocks it builds on do not already produce. Blocks are ordered by
+// combination size so that, as in the token merge, more specific combinations and later axes win.
+func (m Manifest) writeAxisRules(b *strings.Builder, base map[string]string, prefix string, opts StylesheetOptions) {
+	type axisBlock struct {
+		variants []string
+		tokens   map[string]string
+	}
+
+	axisOf := m.variantAxes()
+	var emitted []axisBlock
+	for _, combo := range m.axisCombinations() {
+		cascade := cloneStringMap(base)
+		for _, block := range emitted {
+			if containsAll(combo, block.variants) {
+				for k, v := range block.tokens {
+					cascade[k] = v
+				}
+			}
+		}
+		diff := diffTokens(cascade, m.TokensForVariants(combo...))
+		if len(diff) == 0 {
+			continue
+		}
+		emitted = append(emitted, axisBlock{variants: combo, tokens: diff})
+
+		var atRules []string
+		var compound string
+		for _, variant := range combo {
+			selector := opts.variantSelector(variant, axisOf[variant])
+			if strings.HasPrefix(selector, "@") {
+				atRules = append(atRules, selector)
+			} else {
+				compound += selector
+			}
+		}
+		if compound == "" {
+			compound = ":root"
+		}
+		writeCSSNested(b, atRules, compound, prefix, diff, opts.Minify)
+	}
+}
+
+// variantAxes maps each variant listed on an axis to the first axis naming it.
+func (m Manifest) variantAxes() map[string]string {
+	axisOf := map[string]string{}
+	for _, axis := range m.Axes {
+		for _, value := range axis.Values {
+			if _, ok := axisOf[value]; !ok {
+				axisOf[value] = axis.Name
+			}
+		}
+	}
+	return axisOf
+}
+
+// axisCombinations lists every pick of at most one defined variant per axis (in axis order), smallest first.
+func (m Manifest) axisCombinations() [][]string {
+	combos := [][]string{nil}
+	for _, axis := range m.Axes {
+		next := combos
+		for _, combo := range combos {
+			for _, value := range axis.Values {
+				if _, ok := m.Variants[value]; ok && !containsString(combo, value) {
+					next = append(next, append(cloneStrings(combo), value))
+				}
+			}
+		}
+		combos = next
+	}
+
+	combos = combos[1:]
+	sort.SliceStable(combos, func(i, j int) bool { return len(combos[i]) < len(combos[j]) })
+	return combos
+}
+
+// variantSelector formats the selector for a variant, preferring VariantSelectors, then the axis
+// selector (AxisSelectors or a data attribute named after the axis), then VariantSelector.
+func (o StylesheetOptions) variantSelector(variant, axis string) string {
+	pattern := o.VariantSelector
+	if axis != "" {
+		pattern = o.AxisSelectors[axis]
+	}
+	if override, ok := o.VariantSelectors[variant]; ok && override != "" {
+		pattern = override
+	}
+	if pattern == "" && axis != "" {
+		return `[data-` + escapeCSSIdent(axis) + `="` + escapeCSSIdent(variant) + `"]`
+	}
+	if pattern == "" {
+		pattern = VariantSelectorAttribute
+	}
+	if strings.Contains(pattern, "%s") {
+		return fmt.Sprintf(pattern, escapeCSSIdent(variant))
+	}
+	return pattern
+}
+
+// diffTokens returns the tokens whose value differs from (or is missing in) base.
+func diffTokens(base, tokens map[string]string) map[string]string {
+	diff := make(map[string]string, len(tokens))
+	for k, v := range tokens {
+		if baseValue, ok := base[k]; !ok || baseValue != v {
+			diff[k] = v
+		}
+	}
+	return diff
+}
+
+func containsAll(values, want []string) bool {
+	for _, value := range want {
+		if !containsString(values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Stylesheet renders the selected manifest as CSS; see Manifest.Stylesheet.
+func (s Selection) Stylesheet(opts StylesheetOptions) string {
+	if s.Manifest == nil {
+		return ""
+	}
+	return s.Manifest.Stylesheet(opts)
+}
+
+// writeCSSRule writes a block for selector, wrapping a :root block when selector is an at-rule.
+func writeCSSRule(b *strings.Builder, selector, prefix string, tokens map[string]string, minify bool) {
+	if strings.HasPrefix(selector, "@") {
+		writeCSSNested(b, []string{selector}, ":root", prefix, tokens, minify)
+		return
+	}
+	writeCSSBlock(b, selector, prefix, tokens, minify)
+}
+
+// writeCSSNested writes a block for selector nested inside each at-rule, outermost first.
+func writeCSSNested(b *strings.Builder, atRules []string, selector, prefix string, tokens map[string]string, minify bool) {
+	if len(atRules) == 0 {
+		writeCSSBlock(b, selector, prefix, tokens, minify)
+		return
+	}
+
+	var inner strings.Builder
+	writeCSSNested(&inner, atRules[1:], selector, prefix, tokens, minify)
+	if minify {
+		b.WriteString(atRules[0] + "{" + inner.String() + "}")
+		return
+	}
+
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(atRules[0] + " {\n")
+	for _, line := range strings.Split(strings.TrimSuffix(inner.String(), "\n"), "\n") {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("}\n")
+}
+
+func writeCSSBlock(b *strings.Builder, selector, prefix string, tokens map[string]string, minify bool) {
+	if len(tokens) == 0 {
+		return
+	}
+
+	keys := sortedKeys(tokens)
+	if minify {
+		b.WriteString(selector + "{")
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteString(";")
+			}
+			b.WriteString(prefix + escapeCSSIdent(key) + ":" + escapeCSSValue(tokens[key]))
+		}
+		b.WriteString("}")
+		return
+	}
+
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(selector + " {\n")
+	for _, key := range keys {
+		b.WriteString("  " + prefix + escapeCSSIdent(key) + ": " + escapeCSSValue(tokens[key]) + ";\n")
+	}
+	b.WriteString("}\n")
+}
+
+// escapeCSSIdent backslash-escapes characters that are not valid in a CSS identifier.
+func escapeCSSIdent(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r > 0x7f:
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, "\\%x ", r)
+		default:
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// escapeCSSValue escapes characters that could terminate a declaration or the surrounding <style> element.
+// Comment openers ("/*") and backslashes that would escape one of those characters or the closing ";"
+// are escaped too, and an unbalanced quote is closed so the string ends inside the value.
+func escapeCSSValue(value string) string {
+	runes := []rune(strings.TrimSpace(value))
+	var b strings.Builder
+	var quote rune
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			if i+1 < len(runes) && !cssUnsafeRune(runes[i+1]) {
+				b.WriteRune(r)
+				b.WriteRune(runes[i+1])
+				i++
+				continue
+			}
+			b.WriteString("\\5c ")
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			b.WriteString("\\2f ")
+		case r == '\n' || r == '\r' || r == '\t' || r == '\f':
+			b.WriteRune(' ')
+		case cssUnsafeRune(r):
+			fmt.Fprintf(&b, "\\%x ", r)
+		default:
+			switch {
+			case quote == 0 && (r == '"' || r == '\''):
+				quote = r
+			case r == quote:
+				quote = 0
+			}
+			b.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		b.WriteRune(quote)
+	}
+	return b.String()
+}
+
+// cssUnsafeRune reports runes escapeCSSValue never writes verbatim after a backslash.
+func cssUnsafeRune(r rune) bool {
+	switch r {
+	case ';', '{', '}', '<', '>', '\n', '\r', '\t', '\f':
+		return true
+	}
+	return false
+}
+
+func sortedVariantNames(variants map[string]Variant) []string {
+	names := make([]string, 0, len(variants))
+	for name := range variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,105 @@
+package theme
+
+import "testing"
+
+func stylesheetManifest() Manifest {
+	return Manifest{
+		Name:    "default",
+		Version: "1.0.0",
+		Tokens: map[string]string{
+			"color.primary": "#0044ff",
+			"button.bg":     "{color.primary}",
+			"radius":        "4px",
+		},
+		Variants: map[string]Variant{
+			"dark": {Tokens: map[string]string{"color.primary": "#0f172a", "radius": "4px"}},
+			"same": {Tokens: map[string]string{"radius": "4px"}},
+		},
+	}
+}
+
+func TestStylesheetAttributeSelector(t *testing.T) {
+	css := stylesheetManifest().Stylesheet(StylesheetOptions{})
+
+	expected := `:root {
+  --button\.bg: #0044ff;
+  --color\.primary: #0044ff;
+  --radius: 4px;
+}
+
+[data-theme="dark"] {
+  --button\.bg: #0f172a;
+  --color\.primary: #0f172a;
+}
+`
+	if css != expected {
+		t.Fatalf("unexpected stylesheet:\n%s", css)
+	}
+}
+
+func TestStylesheetMediaQueryAndMinify(t *testing.T) {
+	m := stylesheetManifest()
+
+	css := m.Stylesheet(StylesheetOptions{VariantSelector: VariantSelectorMedia, Prefix: "--app-"})
+	expected := `:root {
+  --app-button\.bg: #0044ff;
+  --app-color\.primary: #0044ff;
+  --app-radius: 4px;
+}
+
+@media (prefers-color-scheme: dark) {
+  :root {
+    --app-button\.bg: #0f172a;
+    --app-color\.primary: #0f172a;
+  }
+}
+`
+	if css != expected {
+		t.Fatalf("unexpected media stylesheet:\n%s", css)
+	}
+
+	minified := m.Stylesheet(StylesheetOptions{
+		VariantSelectors: map[string]string{"dark": VariantSelectorClass},
+		Minify:           true,
+	})
+	expectedMin := `:root{--button\.bg:#0044ff;--color\.primary:#0044ff;--radius:4px}.theme-dark{--button\.bg:#0f172a;--color\.primary:#0f172a}`
+	if minified != expectedMin {
+		t.Fatalf("unexpected minified stylesheet:\n%s", minified)
+	}
+}
+
+func TestStylesheetEscapesValues(t *testing.T) {
+	m := Manifest{Tokens: map[string]string{"evil": "red;}</style><script>"}}
+	css := m.Stylesheet(StylesheetOptions{Minify: true})
+	if css != `:root{--evil:red\3b \7d \3c /style\3e \3c script\3e }` {
+		t.Fatalf("expected escaped value, got %s", css)
+	}
+
+	cases := map[string]string{
+		"a /* b":      `a \2f * b`,
+		`url(a\`:      `url(a\5c `,
+		`a\;b`:        `a\5c \3b b`,
+		`"\201C" \"x`: `"\201C" \"x`,
+		`"open`:       `"open"`,
+		`'it\'s`:      `'it\'s'`,
+		`"a" 'b`:      `"a" 'b'`,
+		"a/b*c \\ d":  `a/b*c \ d`,
+	}
+	for value, want := range cases {
+		css := Manifest{Tokens: map[string]string{"v": value, "w": "blue"}}.Stylesheet(StylesheetOptions{Minify: true})
+		if expected := ":root{--v:" + want + ";--w:blue}"; css != expected {
+			t.Fatalf("%q: expected %s, got %s", value, expected, css)
+		}
+	}
+}
+
+func TestSelectionStylesheet(t *testing.T) {
+	m := stylesheetManifest()
+	sel := Selection{Theme: "default", Manifest: &m}
+	if sel.Stylesheet(StylesheetOptions{}) != m.Stylesheet(StylesheetOptions{}) {
+		t.Fatalf("expected selection stylesheet to match manifest stylesheet")
+	}
+	if (Selection{}).Stylesheet(StylesheetOptions{}) != "" {
+		t.Fatalf("expected empty stylesheet without manifest")
+	}
+}
@@ -0,0 +1,303 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var storeExtensions = map[string]string{
+	"json": ".json",
+	"yaml": ".yaml",
+	"toml": ".toml",
+}
+
+// DirOption configures a DirRegistry.
+type DirOption func(*DirRegistry)
+
+// WithDirStableLatest makes latest-version resolution skip pre-releases while a release exists, like
+// WithStableLatest for MemoryRegistry.
+func WithDirStableLatest() DirOption {
+	return func(r *DirRegistry) {
+		r.stableOnly = true
+	}
+}
+
+// DirRegistry is a Registry and ThemeProvider that persists manifests as files under a directory,
+// one "<root>/<name>/<version>.<ext>" file per version. Lookups follow MemoryRegistry semantics.
+type DirRegistry struct {
+	mu         sync.RWMutex
+	root       string
+	format     string
+	stableOnly bool
+}
+
+// NewDirRegistry creates root when missing and stores manifests in the given format ("json", "yaml"/"yml"
+// or "toml"; empty means JSON). Files in any supported format are read back.
+func NewDirRegistry(root, format string, opts ...DirOption) (*DirRegistry, error) {
+	if strings.TrimSpace(format) == "" {
+		format = "json"
+	}
+	normalized := normalizeFormat(format)
+	if normalized == "" {
+		return nil, fmt.Errorf("unsupported manifest format: %s", format)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create theme directory: %w", err)
+	}
+	r := &DirRegistry{root: root, format: normalized}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// Register validates a manifest and writes it atomically, replacing any stored file for the same
+// name+version.
+func (r *DirRegistry) Register(manifest *Manifest) error {
+	if manifest == nil {
+		return fmt.Errorf("manifest is nil")
+	}
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	if err := validatePathSegment("theme name", manifest.Name); err != nil {
+		return err
+	}
+
+	data, err := encodeManifest(manifest, r.format)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dir := filepath.Join(r.root, manifest.Name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create theme directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	target := filepath.Join(dir, manifest.Version+storeExtensions[r.format])
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	for _, file := range r.versionFiles(manifest.Name, manifest.Version) {
+		if filepath.Join(r.root, filepath.FromSlash(file)) != target {
+			os.Remove(filepath.Join(r.root, filepath.FromSlash(file)))
+		}
+	}
+	return nil
+}
+
+// Unregister deletes a stored version, returning ErrThemeNotFound or ErrVersionNotFound when missing.
+func (r *DirRegistry) Unregister(name, version string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.versions(name); err != nil {
+		return err
+	}
+	files := r.versionFiles(name, strings.TrimSpace(version))
+	if len(files) == 0 {
+		return fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, version)
+	}
+	for _, file := range files {
+		if err := os.Remove(filepath.Join(r.root, filepath.FromSlash(file))); err != nil {
+			return fmt.Errorf("remove manifest: %w", err)
+		}
+	}
+	if remaining, _ := r.versions(name); len(remaining) == 0 {
+		os.Remove(filepath.Join(r.root, name))
+	}
+	return nil
+}
+
+// UnregisterAll deletes every stored version of a theme, returning ErrThemeNotFound when none exists.
+func (r *DirRegistry) UnregisterAll(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.versions(name); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(r.root, name)); err != nil {
+		return fmt.Errorf("remove theme: %w", err)
+	}
+	return nil
+}
+
+// Get loads a manifest by name with the same version, constraint and fallback handling as MemoryRegistry.
+func (r *DirRegistry) Get(name string, opts ...QueryOption) (*Manifest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return getFromStore(r, name, opts, r.stableOnly)
+}
+
+// List returns every readable stored manifest, sorted like MemoryRegistry.List.
+func (r *DirRegistry) List() []ManifestRef {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries, err := os.ReadDir(r.root)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return listStore(r, names, r.stableOnly)
+}
+
+// ListFiltered returns the page of List matching filter.
+func (r *DirRegistry) ListFiltered(filter ListFilter) ManifestPage {
+	return filterManifestRefs(r.List(), filter)
+}
+
+// Theme is an alias for Get to satisfy ThemeProvider.
+func (r *DirRegistry) Theme(name string, opts ...QueryOption) (*Manifest, error) {
+	return r.Get(name, opts...)
+}
+
+// Themes is an alias for List to satisfy ThemeProvider.
+func (r *DirRegistry) Themes() []ManifestRef {
+	return r.List()
+}
+
+// ThemesFiltered is an alias for ListFiltered to satisfy FilteredThemeProvider.
+func (r *DirRegistry) ThemesFiltered(filter ListFilter) ManifestPage {
+	return r.ListFiltered(filter)
+}
+
+// versions lists the stored versions of a theme. Callers must hold r.mu.
+func (r *DirRegistry) versions(name string) ([]string, error) {
+	if validatePathSegment("theme name", name) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	}
+	entries, err := os.ReadDir(filepath.Join(r.root, name))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read theme directory: %w", err)
+	}
+
+	seen := map[string]bool{}
+	var versions []string
+	for _, entry := range entries {
+		version, ok := storedVersion(entry)
+		if ok && !seen[version] {
+			seen[version] = true
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	}
+	return versions, nil
+}
+
+// load reads a stored version through LoadFile. Callers must hold r.mu.
+func (r *DirRegistry) load(name, version string) (*Manifest, error) {
+	files := r.versionFiles(name, version)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, version)
+	}
+	return LoadFile(os.DirFS(r.root), files[0])
+}
+
+// versionFiles returns the slash-separated paths (relative to root) storing name@version, preferring
+// the configured format. Callers must hold r.mu.
+func (r *DirRegistry) versionFiles(name, version string) []string {
+	if validatePathSegment("theme name", name) != nil || validatePathSegment("version", version) != nil {
+		return nil
+	}
+	formats := []string{r.format, "json", "yaml", "toml"}
+	seen := map[string]bool{}
+	var files []string
+	for _, format := range formats {
+		exts := []string{storeExtensions[format]}
+		if format == "yaml" {
+			exts = append(exts, ".yml")
+		}
+		for _, ext := range exts {
+			file := path.Join(name, version+ext)
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			if info, err := os.Stat(filepath.Join(r.root, filepath.FromSlash(file))); err == nil && !info.IsDir() {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+func storedVersion(entry fs.DirEntry) (string, bool) {
+	if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+		return "", false
+	}
+	ext := path.Ext(entry.Name())
+	if normalizeFormat(strings.TrimPrefix(ext, ".")) == "" {
+		return "", false
+	}
+	return strings.TrimSuffix(entry.Name(), ext), true
+}
+
+// validatePathSegment rejects values that cannot be used as a single file or directory name.
+func validatePathSegment(label, value string) error {
+	if value == "" || value == "." || value == ".." || strings.HasPrefix(value, ".") || strings.ContainsAny(value, "/\\\x00") {
+		return fmt.Errorf("%s %q cannot be used as a file name", label, value)
+	}
+	return nil
+}
+
+// encodeManifest serializes a manifest in the given normalized format.
+func encodeManifest(manifest *Manifest, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode manifest: %w", err)
+		}
+		return append(data, '\n'), nil
+	case "yaml":
+		data, err := yaml.Marshal(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("encode manifest: %w", err)
+		}
+		return data, nil
+	case "toml":
+		var buf strings.Builder
+		if err := toml.NewEncoder(&buf).Encode(manifest); err != nil {
+			return nil, fmt.Errorf("encode manifest: %w", err)
+		}
+		return []byte(buf.String()), nil
+	default:
+		return nil, fmt.Errorf("unsupported manifest format: %s", format)
+	}
+}
@@ -0,0 +1,73 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirRegistryContract(t *testing.T) {
+	for _, format := range []string{"json", "yaml", "toml"} {
+		t.Run(format, func(t *testing.T) {
+			reg, err := NewDirRegistry(t.TempDir(), format)
+			if err != nil {
+				t.Fatalf("new dir registry: %v", err)
+			}
+			testPersistentRegistry(t, reg)
+		})
+	}
+}
+
+func TestDirRegistryPersistsAcrossInstances(t *testing.T) {
+	root := t.TempDir()
+	reg, err := NewDirRegistry(root, "yaml")
+	if err != nil {
+		t.Fatalf("new dir registry: %v", err)
+	}
+	if err := reg.Register(&Manifest{Name: "acme", Version: "1.0.0", Tokens: map[string]string{"primary": "blue"}}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "acme", "1.0.0.yaml")); err != nil {
+		t.Fatalf("expected yaml file on disk: %v", err)
+	}
+
+	reopened, err := NewDirRegistry(root, "json")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	m, err := reopened.Get("acme")
+	if err != nil || m.Tokens["primary"] != "blue" {
+		t.Fatalf("expected manifest to be read back, got %+v (%v)", m, err)
+	}
+
+	if err := reopened.Register(&Manifest{Name: "acme", Version: "1.0.0", Tokens: map[string]string{"primary": "red"}}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "acme", "1.0.0.yaml")); !os.IsNotExist(err) {
+		t.Fatalf("expected the yaml copy to be replaced by json, got %v", err)
+	}
+
+	if err := reopened.Register(&Manifest{Name: "../escape", Version: "1.0.0"}); err == nil {
+		t.Fatalf("expected unsafe theme name to be rejected")
+	}
+}
+
+func TestDirRegistryStableLatest(t *testing.T) {
+	reg, err := NewDirRegistry(t.TempDir(), "json", WithDirStableLatest())
+	if err != nil {
+		t.Fatalf("new dir registry: %v", err)
+	}
+	for _, version := range []string{"1.1.0", "2.0.0-beta.1"} {
+		if err := reg.Register(&Manifest{Name: "acme", Version: version}); err != nil {
+			t.Fatalf("register %s: %v", version, err)
+		}
+	}
+	if m, err := reg.Get("acme"); err != nil || m.Version != "1.1.0" {
+		t.Fatalf("expected latest stable 1.1.0, got %+v (%v)", m, err)
+	}
+	for _, ref := range reg.List() {
+		if ref.Latest != (ref.Version == "1.1.0") {
+			t.Fatalf("expected only the stable release marked latest, got %+v", ref)
+		}
+	}
+}
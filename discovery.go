@@ -0,0 +1,220 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// DiscoverOption configures LoadAll.
+type DiscoverOption func(*discoverOptions)
+
+type discoverOptions struct {
+	maxDepth    int
+	include     []string
+	exclude     []string
+	loadOptions LoadOptions
+}
+
+// WithMaxDepth limits how deep LoadAll descends below the root; 0 only inspects the root itself.
+// Negative values (the default) mean no limit.
+func WithMaxDepth(depth int) DiscoverOption {
+	return func(opts *discoverOptions) {
+		opts.maxDepth = depth
+	}
+}
+
+// WithInclude only loads manifests from directories matching at least one path.Match pattern.
+// Patterns are matched against the directory path relative to the root and against its base name.
+func WithInclude(patterns ...string) DiscoverOption {
+	return func(opts *discoverOptions) {
+		opts.include = append(opts.include, patterns...)
+	}
+}
+
+// WithExclude skips directories (and everything below them) matching any path.Match pattern.
+// Patterns are matched against the directory path relative to the root and against its base name.
+func WithExclude(patterns ...string) DiscoverOption {
+	return func(opts *discoverOptions) {
+		opts.exclude = append(opts.exclude, patterns...)
+	}
+}
+
+// WithLoadOptions passes LoadOptions (strict decoding, schema validation) to every manifest load.
+func WithLoadOptions(opts LoadOptions) DiscoverOption {
+	return func(settings *discoverOptions) {
+		settings.loadOptions = opts
+	}
+}
+
+// LoadedTheme records a manifest registered by LoadAll.
+type LoadedTheme struct {
+	Dir     string
+	Path    string
+	Name    string
+	Version string
+}
+
+// DirError records why a directory's manifest could not be loaded or registered.
+type DirError struct {
+	Dir  string
+	Path string
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *DirError) Error() string {
+	return fmt.Sprintf("load theme in %s: %v", e.Dir, e.Err)
+}
+
+// Unwrap returns the underlying load or register error.
+func (e *DirError) Unwrap() error {
+	return e.Err
+}
+
+// LoadReport aggregates the outcome of LoadAll in walk (lexical) order.
+type LoadReport struct {
+	Loaded []LoadedTheme
+	Errors []*DirError
+}
+
+// Err joins every per-directory error, or returns nil when all manifests loaded.
+func (r *LoadReport) Err() error {
+	if r == nil || len(r.Errors) == 0 {
+		return nil
+	}
+	errs := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err
+	}
+	return errors.Join(errs...)
+}
+
+// LoadAll walks root within fsys, loads the manifest of every directory that contains one (using the
+// same filename precedence as LoadDir) and registers it. Failures are collected per directory in the
+// report instead of stopping the walk; the returned error is only set when the walk itself fails.
+// A name@version found in more than one directory is registered once and reported for the others.
+func LoadAll(fsys fs.FS, root string, registry Registry, opts ...DiscoverOption) (*LoadReport, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("theme registry is nil")
+	}
+
+	settings := newDiscoverOptions(opts)
+	report := &LoadReport{}
+	seen := map[string]string{}
+
+	err := walkManifests(fsys, root, settings, func(dir, manifestPath string, err error) {
+		if err != nil {
+			report.Errors = append(report.Errors, &DirError{Dir: dir, Path: manifestPath, Err: err})
+			return
+		}
+
+		manifest, err := LoadFile(fsys, manifestPath, settings.loadOptions)
+		if err != nil {
+			report.Errors = append(report.Errors, &DirError{Dir: dir, Path: manifestPath, Err: err})
+			return
+		}
+
+		key := manifestKey(manifest)
+		if previous, ok := seen[key]; ok {
+			report.Errors = append(report.Errors, &DirError{
+				Dir:  dir,
+				Path: manifestPath,
+				Err:  fmt.Errorf("theme %s already loaded from %s", key, previous),
+			})
+			return
+		}
+
+		if err := registry.Register(manifest); err != nil {
+			report.Errors = append(report.Errors, &DirError{Dir: dir, Path: manifestPath, Err: err})
+			return
+		}
+		seen[key] = manifestPath
+		report.Loaded = append(report.Loaded, LoadedTheme{
+			Dir:     dir,
+			Path:    manifestPath,
+			Name:    manifest.Name,
+			Version: manifest.Version,
+		})
+	})
+	return report, err
+}
+
+func newDiscoverOptions(opts []DiscoverOption) discoverOptions {
+	settings := discoverOptions{maxDepth: -1}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	return settings
+}
+
+// walkManifests calls visit for every directory below root holding a manifest, honoring depth and globs.
+// Unreadable subdirectories are passed to visit with a non-nil error; only a failing root aborts the walk.
+func walkManifests(fsys fs.FS, root string, settings discoverOptions, visit func(dir, manifestPath string, err error)) error {
+	root = path.Clean(root)
+	err := fs.WalkDir(fsys, root, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if dir == root {
+				return err
+			}
+			visit(dir, "", err)
+			return nil
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+
+		rel := relativeDir(root, dir)
+		if dir != root && matchesAny(settings.exclude, rel) {
+			return fs.SkipDir
+		}
+		if settings.maxDepth >= 0 && dirDepth(rel) > settings.maxDepth {
+			return fs.SkipDir
+		}
+		if len(settings.include) > 0 && !matchesAny(settings.include, rel) {
+			return nil
+		}
+
+		if manifestPath, ok := findManifest(fsys, dir); ok {
+			visit(dir, manifestPath, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("walk themes in %s: %w", root, err)
+	}
+	return nil
+}
+
+// relativeDir returns dir relative to root using slash separators, "." for the root itself.
+func relativeDir(root, dir string) string {
+	if dir == root {
+		return "."
+	}
+	if root == "." {
+		return dir
+	}
+	return strings.TrimPrefix(dir, root+"/")
+}
+
+func dirDepth(rel string) int {
+	if rel == "." {
+		return 0
+	}
+	return strings.Count(rel, "/") + 1
+}
+
+func matchesAny(patterns []string, rel string) bool {
+	base := path.Base(rel)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
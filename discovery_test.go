@@ -0,0 +1,79 @@
+package theme
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func discoveryFS() fstest.MapFS {
+	return fstest.MapFS{
+		"themes/acme/theme.yaml":              &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\n")},
+		"themes/acme/manifest.json":           &fstest.MapFile{Data: []byte(`{"name":"ignored","version":"1.0.0"}`)},
+		"themes/brand/theme.toml":             &fstest.MapFile{Data: []byte("name = \"brand\"\nversion = \"2.0.0\"\n")},
+		"themes/broken/theme.json":            &fstest.MapFile{Data: []byte(`{"name": "broken",`)},
+		"themes/invalid/theme.yaml":           &fstest.MapFile{Data: []byte("name: invalid\n")},
+		"themes/copy/theme.yaml":              &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\n")},
+		"themes/nested/deep/theme.yaml":       &fstest.MapFile{Data: []byte("name: deep\nversion: 1.0.0\n")},
+		"themes/node_modules/pkg/theme.yaml":  &fstest.MapFile{Data: []byte("name: vendored\nversion: 1.0.0\n")},
+		"themes/empty/README.md":              &fstest.MapFile{Data: []byte("no manifest here")},
+		"themes/nested/deep/templates/a.tmpl": &fstest.MapFile{Data: []byte("{{ . }}")},
+	}
+}
+
+func TestLoadAllRegistersAndReportsErrors(t *testing.T) {
+	reg := NewRegistry()
+	report, err := LoadAll(discoveryFS(), "themes", reg, WithExclude("node_modules"))
+	if err != nil {
+		t.Fatalf("unexpected walk error: %v", err)
+	}
+
+	var loaded []string
+	for _, theme := range report.Loaded {
+		loaded = append(loaded, theme.Name+"@"+theme.Version+":"+theme.Path)
+	}
+	want := "acme@1.0.0:themes/acme/theme.yaml,brand@2.0.0:themes/brand/theme.toml,deep@1.0.0:themes/nested/deep/theme.yaml"
+	if got := strings.Join(loaded, ","); got != want {
+		t.Fatalf("expected loaded %s, got %s", want, got)
+	}
+	if len(reg.List()) != 3 {
+		t.Fatalf("expected 3 registered themes, got %+v", reg.List())
+	}
+
+	dirs := map[string]error{}
+	for _, dirErr := range report.Errors {
+		dirs[dirErr.Dir] = dirErr
+	}
+	if len(dirs) != 3 || dirs["themes/broken"] == nil || dirs["themes/invalid"] == nil || dirs["themes/copy"] == nil {
+		t.Fatalf("expected errors for broken, invalid and copy, got %v", report.Err())
+	}
+	var decodeErr *DecodeError
+	if !errors.As(dirs["themes/broken"], &decodeErr) {
+		t.Fatalf("expected DecodeError for broken theme, got %v", dirs["themes/broken"])
+	}
+	var validationErr ValidationError
+	if !errors.As(report.Err(), &validationErr) {
+		t.Fatalf("expected joined error to expose the ValidationError")
+	}
+}
+
+func TestLoadAllDepthAndGlobs(t *testing.T) {
+	reg := NewRegistry()
+	report, err := LoadAll(discoveryFS(), "themes", reg, WithMaxDepth(1), WithInclude("a*", "b*"))
+	if err != nil {
+		t.Fatalf("unexpected walk error: %v", err)
+	}
+	if len(report.Loaded) != 2 || report.Loaded[0].Name != "acme" || report.Loaded[1].Name != "brand" {
+		t.Fatalf("expected only acme and brand, got %+v", report.Loaded)
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Dir != "themes/broken" {
+		t.Fatalf("expected only the included broken dir to fail, got %v", report.Err())
+	}
+}
+
+func TestLoadAllMissingRoot(t *testing.T) {
+	if _, err := LoadAll(fstest.MapFS{}, "themes", NewRegistry()); err == nil {
+		t.Fatalf("expected error for missing root")
+	}
+}
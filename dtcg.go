@@ -0,0 +1,253 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DTCGOptions controls how W3C Design Tokens (DTCG) documents map to manifest token names.
+type DTCGOptions struct {
+	// Separator joins group path segments into flat token names. Defaults to ".".
+	Separator string
+}
+
+func (o DTCGOptions) separator() string {
+	if o.Separator == "" {
+		return "."
+	}
+	return o.Separator
+}
+
+// dtcgTypes maps DTCG $type names to manifest token types.
+var dtcgTypes = map[string]TokenType{
+	"color":       TokenTypeColor,
+	"dimension":   TokenTypeDimension,
+	"duration":    TokenTypeDuration,
+	"fontFamily":  TokenTypeFontFamily,
+	"fontWeight":  TokenTypeFontWeight,
+	"number":      TokenTypeNumber,
+	"shadow":      TokenTypeShadow,
+	"cubicBezier": TokenTypeCubicBezier,
+}
+
+// LoadDTCG converts a DTCG JSON document into a Manifest holding the flattened tokens and their types.
+// The returned manifest only carries tokens; set Name/Version (or merge the tokens into
+// an existing manifest) before registering it.
+func LoadDTCG(data []byte, opts DTCGOptions) (*Manifest, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("dtcg decode: %w", err)
+	}
+
+	manifest := &Manifest{Tokens: map[string]string{}, TokenTypes: map[string]string{}}
+	if err := flattenDTCGGroup(manifest, doc, nil, "", opts.separator()); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ExportDTCG writes the tokens of a manifest variant (merged with its fallback chain and base tokens) as a DTCG JSON document.
+// Token references are preserved as DTCG aliases.
+func ExportDTCG(manifest Manifest, variant string, opts DTCGOptions) ([]byte, error) {
+	sep := opts.separator()
+	tokens := manifest.variantTokens(variant)
+
+	doc := map[string]any{}
+	for _, name := range sortedKeys(tokens) {
+		segments := strings.Split(name, sep)
+		group := doc
+		for i, segment := range segments[:len(segments)-1] {
+			next, ok := group[segment]
+			if !ok {
+				next = map[string]any{}
+				group[segment] = next
+			}
+			child, ok := next.(map[string]any)
+			if !ok || isDTCGToken(child) {
+				return nil, fmt.Errorf("dtcg export: token %s conflicts with token %s", name, strings.Join(segments[:i+1], sep))
+			}
+			group = child
+		}
+
+		leaf := segments[len(segments)-1]
+		if _, exists := group[leaf]; exists {
+			return nil, fmt.Errorf("dtcg export: token %s conflicts with a group of the same name", name)
+		}
+		group[leaf] = dtcgToken(convertReferences(tokens[name], sep, "."), TokenType(manifest.TokenTypes[name]))
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// dtcgToken builds a DTCG token entry, emitting typed values where the manifest declares a type.
+func dtcgToken(value string, tokenType TokenType) map[string]any {
+	token := map[string]any{"$value": value}
+	for name, mapped := range dtcgTypes {
+		if mapped == tokenType {
+			token["$type"] = name
+		}
+	}
+	if tokenReferencePattern.MatchString(value) {
+		return token
+	}
+
+	switch tokenType {
+	case TokenTypeNumber, TokenTypeFontWeight:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			token["$value"] = n
+		}
+	case TokenTypeFontFamily:
+		families := splitTopLevel(value, ',')
+		for i := range families {
+			families[i] = strings.TrimSpace(families[i])
+		}
+		token["$value"] = families
+	case TokenTypeCubicBezier:
+		if match := bezierPattern.FindStringSubmatch(value); match != nil {
+			points := make([]float64, 0, 4)
+			for _, raw := range match[1:] {
+				n, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+				if err != nil {
+					return token
+				}
+				points = append(points, n)
+			}
+			token["$value"] = points
+		}
+	}
+	return token
+}
+
+func flattenDTCGGroup(manifest *Manifest, group map[string]any, path []string, inheritedType, sep string) error {
+	if t, ok := group["$type"].(string); ok {
+		inheritedType = t
+	}
+
+	keys := make([]string, 0, len(group))
+	for key := range group {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		entryPath := append(append([]string{}, path...), key)
+		entry, ok := group[key].(map[string]any)
+		if !ok {
+			return fmt.Errorf("dtcg: %s must be a token or group object", strings.Join(entryPath, "."))
+		}
+
+		if !isDTCGToken(entry) {
+			if err := flattenDTCGGroup(manifest, entry, entryPath, inheritedType, sep); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tokenType := inheritedType
+		if t, ok := entry["$type"].(string); ok {
+			tokenType = t
+		}
+		value, err := dtcgValueString(entry["$value"], tokenType)
+		if err != nil {
+			return fmt.Errorf("dtcg: %s: %w", strings.Join(entryPath, "."), err)
+		}
+		name := strings.Join(entryPath, sep)
+		manifest.Tokens[name] = convertReferences(value, ".", sep)
+		if mapped, ok := dtcgTypes[tokenType]; ok {
+			manifest.TokenTypes[name] = string(mapped)
+		}
+	}
+	return nil
+}
+
+func isDTCGToken(entry map[string]any) bool {
+	_, ok := entry["$value"]
+	return ok
+}
+
+// dtcgValueString renders a DTCG $value as a CSS-friendly string.
+func dtcgValueString(value any, tokenType string) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			part, err := dtcgValueString(item, tokenType)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = part
+		}
+		if tokenType == "cubicBezier" {
+			return "cubic-bezier(" + strings.Join(parts, ", ") + ")", nil
+		}
+		return strings.Join(parts, ", "), nil
+	case map[string]any:
+		if amount, ok := v["value"]; ok {
+			if unit, ok := v["unit"].(string); ok {
+				number, err := dtcgValueString(amount, "")
+				if err != nil {
+					return "", err
+				}
+				return number + unit, nil
+			}
+		}
+		if tokenType == "shadow" {
+			return dtcgShadowString(v)
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	case nil:
+		return "", fmt.Errorf("$value is null")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+func dtcgShadowString(shadow map[string]any) (string, error) {
+	var parts []string
+	if inset, _ := shadow["inset"].(bool); inset {
+		parts = append(parts, "inset")
+	}
+	for _, key := range []string{"offsetX", "offsetY", "blur", "spread", "color"} {
+		value, ok := shadow[key]
+		if !ok {
+			continue
+		}
+		part, err := dtcgValueString(value, "")
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// convertReferences rewrites "{a.b}" references from one path separator to another.
+func convertReferences(value, from, to string) string {
+	if from == to {
+		return value
+	}
+	return tokenReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+		ref := strings.TrimSpace(match[1 : len(match)-1])
+		return "{" + strings.ReplaceAll(ref, from, to) + "}"
+	})
+}
@@ -0,0 +1,110 @@
+package theme
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const dtcgSample = `{
+  "color": {
+    "$type": "color",
+    "primary": {"$value": "#0044ff", "$description": "Brand primary"},
+    "link": {"$value": "{color.primary}"}
+  },
+  "space": {
+    "md": {"$type": "dimension", "$value": {"value": 16, "unit": "px"}}
+  },
+  "font": {
+    "body": {"$type": "fontFamily", "$value": ["Inter", "sans-serif"]},
+    "weight": {"$type": "fontWeight", "$value": 600}
+  },
+  "easing": {"$type": "cubicBezier", "$value": [0.4, 0, 0.2, 1]},
+  "elevation": {
+    "$type": "shadow",
+    "low": {"$value": {"color": "#00000033", "offsetX": "0px", "offsetY": "1px", "blur": "2px", "spread": "0px"}}
+  }
+}`
+
+func TestLoadDTCGFlattensGroups(t *testing.T) {
+	manifest, err := LoadDTCG([]byte(dtcgSample), DTCGOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"color.primary": "#0044ff",
+		"color.link":    "{color.primary}",
+		"space.md":      "16px",
+		"font.body":     "Inter, sans-serif",
+		"font.weight":   "600",
+		"easing":        "cubic-bezier(0.4, 0, 0.2, 1)",
+		"elevation.low": "0px 1px 2px 0px #00000033",
+	}
+	for key, want := range expected {
+		if got := manifest.Tokens[key]; got != want {
+			t.Fatalf("expected %s=%q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestLoadDTCGCustomSeparator(t *testing.T) {
+	manifest, err := LoadDTCG([]byte(dtcgSample), DTCGOptions{Separator: "-"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.Tokens["color-primary"] != "#0044ff" {
+		t.Fatalf("expected separator to be applied, got %v", manifest.Tokens)
+	}
+	if manifest.Tokens["color-link"] != "{color-primary}" {
+		t.Fatalf("expected reference to use separator, got %s", manifest.Tokens["color-link"])
+	}
+}
+
+func TestLoadDTCGRejectsInvalidEntries(t *testing.T) {
+	if _, err := LoadDTCG([]byte(`{"color": {"primary": "#fff"}}`), DTCGOptions{}); err == nil {
+		t.Fatalf("expected error for token without $value object")
+	}
+}
+
+func TestExportDTCGRoundTrip(t *testing.T) {
+	m := Manifest{
+		Name:    "default",
+		Version: "1.0.0",
+		Tokens: map[string]string{
+			"color-primary": "#0044ff",
+			"color-link":    "{color-primary}",
+		},
+		Variants: map[string]Variant{
+			"dark": {Tokens: map[string]string{"color-primary": "#0f172a"}},
+		},
+	}
+	opts := DTCGOptions{Separator: "-"}
+
+	data, err := ExportDTCG(m, "dark", opts)
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+
+	var doc map[string]map[string]map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("export is not valid json: %v", err)
+	}
+	if doc["color"]["link"]["$value"] != "{color.primary}" {
+		t.Fatalf("expected DTCG alias, got %v", doc["color"]["link"])
+	}
+
+	loaded, err := LoadDTCG(data, opts)
+	if err != nil {
+		t.Fatalf("unexpected import error: %v", err)
+	}
+	if loaded.Tokens["color-primary"] != "#0f172a" || loaded.Tokens["color-link"] != "{color-primary}" {
+		t.Fatalf("round trip mismatch: %v", loaded.Tokens)
+	}
+}
+
+func TestExportDTCGRejectsConflicts(t *testing.T) {
+	m := Manifest{Tokens: map[string]string{"color": "#fff", "color.primary": "#000"}}
+	if _, err := ExportDTCG(m, "", DTCGOptions{}); err == nil {
+		t.Fatalf("expected conflict error when a token is also a group")
+	}
+}
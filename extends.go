@@ -0,0 +1,159 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrParentNotFound is returned when a manifest extends a theme that cannot be resolved.
+	ErrParentNotFound = errors.New("parent theme not found")
+	// ErrExtendsCycle is returned when a manifest inheritance chain loops back on itself.
+	ErrExtendsCycle = errors.New("theme extends cycle")
+)
+
+// manifestLookup fetches a stored manifest by name and optional version.
+type manifestLookup func(name, version string) (*Manifest, error)
+
+// parseExtends splits an extends reference of the form "name@version" or "name@constraint" into its parts.
+func parseExtends(ref string) (name, version string) {
+	ref = strings.TrimSpace(ref)
+	if idx := strings.Index(ref, "@"); idx >= 0 {
+		return strings.TrimSpace(ref[:idx]), strings.TrimSpace(ref[idx+1:])
+	}
+	return ref, ""
+}
+
+// parentQuery maps the version part of an extends reference onto lookup settings: an exact SemVer pins
+// that version, anything else (e.g. "^1.2") is treated as a constraint. Misses never fall back.
+func parentQuery(version string) queryOptions {
+	settings := queryOptions{fallback: FallbackError}
+	if _, err := parseSemVer(version); err == nil || version == "" {
+		settings.version = version
+	} else {
+		settings.constraint = version
+	}
+	return settings
+}
+
+// flattenManifest walks the extends chain of a manifest and returns a copy with all parent values merged in.
+// Values declared closer to the requested manifest take precedence over inherited ones. The result is
+// validated, so checks skipped while registering a child (e.g. references to parent tokens) apply here.
+func flattenManifest(manifest *Manifest, lookup manifestLookup) (*Manifest, error) {
+	if manifest == nil {
+		return nil, fmt.Errorf("manifest is nil")
+	}
+	if strings.TrimSpace(manifest.Extends) == "" {
+		return copyManifest(manifest), nil
+	}
+
+	chain := []*Manifest{manifest}
+	seen := map[string]bool{manifestKey(manifest): true}
+	current := manifest
+
+	for strings.TrimSpace(current.Extends) != "" {
+		name, version := parseExtends(current.Extends)
+		parent, err := lookup(name, version)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s extends %s: %w", ErrParentNotFound, manifestKey(current), current.Extends, err)
+		}
+
+		key := manifestKey(parent)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s extends %s", ErrExtendsCycle, manifestKey(current), key)
+		}
+		seen[key] = true
+
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	flattened := copyManifest(chain[len(chain)-1])
+	for i := len(chain) - 2; i >= 0; i-- {
+		mergeManifest(flattened, chain[i])
+	}
+	flattened.Extends = ""
+	if err := flattened.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestKey(manifest), err)
+	}
+	return flattened, nil
+}
+
+// mergeManifest applies override values onto dst, merging maps key by key.
+func mergeManifest(dst, override *Manifest) {
+	dst.Name = override.Name
+	dst.Version = override.Version
+	if strings.TrimSpace(override.Description) != "" {
+		dst.Description = override.Description
+	}
+	if strings.TrimSpace(override.Author) != "" {
+		dst.Author = override.Author
+	}
+	if len(override.Tags) > 0 {
+		dst.Tags = append([]string(nil), override.Tags...)
+	}
+	if strings.TrimSpace(override.Preview) != "" {
+		dst.Preview = override.Preview
+	}
+
+	dst.Tokens = mergeStringMaps(dst.Tokens, override.Tokens)
+	dst.TokenTypes = mergeStringMaps(dst.TokenTypes, override.TokenTypes)
+	dst.Fonts = mergeStringMaps(dst.Fonts, override.Fonts)
+	dst.Templates = mergeStringMaps(dst.Templates, override.Templates)
+	dst.Assets = mergeAssets(dst.Assets, override.Assets)
+
+	if dst.Variants == nil {
+		dst.Variants = make(map[string]Variant, len(override.Variants))
+	}
+	for name, variant := range override.Variants {
+		base := dst.Variants[name]
+		if strings.TrimSpace(variant.Description) != "" {
+			base.Description = variant.Description
+		}
+		if strings.TrimSpace(variant.Fallback) != "" {
+			base.Fallback = variant.Fallback
+		}
+		base.Tokens = mergeStringMaps(base.Tokens, variant.Tokens)
+		base.Templates = mergeStringMaps(base.Templates, variant.Templates)
+		base.Assets = mergeAssets(base.Assets, variant.Assets)
+		dst.Variants[name] = base
+	}
+	dst.Axes = mergeAxes(dst.Axes, override.Axes)
+}
+
+// mergeAxes replaces base axes with same-named overrides and appends new axes after them.
+func mergeAxes(base, override []VariantAxis) []VariantAxis {
+	for _, axis := range override {
+		axis.Values = cloneStrings(axis.Values)
+		replaced := false
+		for i := range base {
+			if base[i].Name == axis.Name {
+				base[i], replaced = axis, true
+			}
+		}
+		if !replaced {
+			base = append(base, axis)
+		}
+	}
+	return base
+}
+
+func mergeAssets(base, override Assets) Assets {
+	return Assets{
+		Prefix: activePrefix(base.Prefix, override.Prefix),
+		Files:  mergeStringMaps(base.Files, override.Files),
+	}
+}
+
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	merged := cloneStringMap(base)
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+func manifestKey(manifest *Manifest) string {
+	return manifest.Name + "@" + manifest.Version
+}
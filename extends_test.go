@@ -0,0 +1,241 @@
+package theme
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRegistryFlattensExtends(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{
+		Name:    "brand-b",
+		Version: "1.0.0",
+		Extends: "acme-admin@1.0.0",
+		Tokens:  map[string]string{"primary": "#ff0000"},
+		Variants: map[string]Variant{
+			"dark": {Tokens: map[string]string{"primary": "#330000"}},
+		},
+	})
+	reg.Register(&Manifest{
+		Name:        "acme-admin",
+		Version:     "1.0.0",
+		Description: "Acme admin",
+		Tokens:      map[string]string{"primary": "#0044ff", "accent": "#ffaa00"},
+		Fonts:       map[string]string{"body": "Inter"},
+		Assets: Assets{
+			Prefix: "/static/acme",
+			Files:  map[string]string{"logo": "logo.svg"},
+		},
+		Templates: map[string]string{"layout.header": "acme/header.tmpl"},
+		Variants: map[string]Variant{
+			"dark": {
+				Tokens:    map[string]string{"bg": "#000"},
+				Templates: map[string]string{"layout.header": "acme/dark/header.tmpl"},
+			},
+		},
+	})
+
+	manifest, err := reg.Get("brand-b")
+	if err != nil {
+		t.Fatalf("unexpected error resolving extends: %v", err)
+	}
+	if manifest.Name != "brand-b" || manifest.Extends != "" {
+		t.Fatalf("expected flattened brand-b manifest, got %+v", manifest)
+	}
+	if manifest.Description != "Acme admin" {
+		t.Fatalf("expected inherited description, got %s", manifest.Description)
+	}
+	if manifest.Tokens["primary"] != "#ff0000" || manifest.Tokens["accent"] != "#ffaa00" {
+		t.Fatalf("expected merged tokens, got %v", manifest.Tokens)
+	}
+	if manifest.Fonts["body"] != "Inter" || manifest.Assets.Prefix != "/static/acme" {
+		t.Fatalf("expected inherited fonts and assets, got %+v", manifest)
+	}
+
+	dark := manifest.Variants["dark"]
+	if dark.Tokens["primary"] != "#330000" || dark.Tokens["bg"] != "#000" {
+		t.Fatalf("expected merged variant tokens, got %v", dark.Tokens)
+	}
+	if dark.Templates["layout.header"] != "acme/dark/header.tmpl" {
+		t.Fatalf("expected inherited variant template, got %v", dark.Templates)
+	}
+
+	sel, err := Selector{Registry: reg}.Select("brand-b", "dark")
+	if err != nil {
+		t.Fatalf("unexpected error selecting: %v", err)
+	}
+	snapshot := sel.Snapshot()
+	if snapshot.Assets["logo"] != "/static/acme/logo.svg" {
+		t.Fatalf("expected snapshot to include inherited asset, got %v", snapshot.Assets)
+	}
+	if snapshot.Templates["layout.header"] != "acme/dark/header.tmpl" {
+		t.Fatalf("expected snapshot to include inherited template, got %v", snapshot.Templates)
+	}
+}
+
+func TestRegistryExtendsMultipleLevels(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "base", Version: "1.0.0", Tokens: map[string]string{"a": "1", "b": "1", "c": "1"}})
+	reg.Register(&Manifest{Name: "mid", Version: "1.0.0", Extends: "base", Tokens: map[string]string{"b": "2", "c": "2"}})
+	reg.Register(&Manifest{Name: "leaf", Version: "1.0.0", Extends: "mid", Tokens: map[string]string{"c": "3"}})
+
+	manifest, err := reg.Get("leaf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.Tokens["a"] != "1" || manifest.Tokens["b"] != "2" || manifest.Tokens["c"] != "3" {
+		t.Fatalf("expected nearest values to win, got %v", manifest.Tokens)
+	}
+}
+
+func TestRegistryExtendsMissingParent(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "child", Version: "1.0.0", Extends: "ghost", Tokens: map[string]string{"a": "1"}})
+
+	_, err := reg.Get("child")
+	if !errors.Is(err, ErrParentNotFound) {
+		t.Fatalf("expected ErrParentNotFound, got %v", err)
+	}
+	if !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected wrapped ErrThemeNotFound, got %v", err)
+	}
+
+	reg.Register(&Manifest{Name: "ghost", Version: "1.0.0", Tokens: map[string]string{"a": "0"}})
+	reg.Register(&Manifest{Name: "pinned", Version: "1.0.0", Extends: "ghost@2.0.0", Tokens: map[string]string{"a": "1"}})
+	if _, err := reg.Get("pinned"); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound for pinned parent, got %v", err)
+	}
+}
+
+func TestRegistryExtendsParentConstraint(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "parent", Version: "1.0.0", Tokens: map[string]string{"primary": "red"}})
+	reg.Register(&Manifest{Name: "parent", Version: "1.4.0", Tokens: map[string]string{"primary": "blue"}})
+	reg.Register(&Manifest{Name: "parent", Version: "2.0.0", Tokens: map[string]string{"primary": "green"}})
+	reg.Register(&Manifest{Name: "child", Version: "1.0.0", Extends: "parent@^1.0"})
+	reg.Register(&Manifest{Name: "stale", Version: "1.0.0", Extends: "parent@^3"})
+
+	manifest, err := reg.Get("child")
+	if err != nil {
+		t.Fatalf("unexpected error resolving constrained parent: %v", err)
+	}
+	if manifest.Tokens["primary"] != "blue" {
+		t.Fatalf("expected highest parent matching ^1.0, got %s", manifest.Tokens["primary"])
+	}
+
+	if _, err := reg.Get("stale"); !errors.Is(err, ErrParentNotFound) || !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrParentNotFound for unmatched constraint, got %v", err)
+	}
+}
+
+func TestRegistryExtendsParentTokenReferences(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(&Manifest{
+		Name:       "base",
+		Version:    "1.0.0",
+		Tokens:     map[string]string{"color.primary": "#0044ff", "space": "4px"},
+		TokenTypes: map[string]string{"color.primary": "color", "space": "dimension"},
+	}); err != nil {
+		t.Fatalf("register base: %v", err)
+	}
+	if err := reg.Register(&Manifest{
+		Name:    "brand",
+		Version: "1.0.0",
+		Extends: "base@^1",
+		Tokens:  map[string]string{"button.bg": "{color.primary}"},
+	}); err != nil {
+		t.Fatalf("expected a reference to a parent token to register, got %v", err)
+	}
+
+	brand, err := reg.Get("brand")
+	if err != nil {
+		t.Fatalf("get brand: %v", err)
+	}
+	if brand.TokensForVariant("")["button.bg"] != "#0044ff" {
+		t.Fatalf("expected the parent token to resolve, got %v", brand.TokensForVariant(""))
+	}
+
+	if err := reg.Register(&Manifest{Name: "broken", Version: "1.0.0", Extends: "base", Tokens: map[string]string{"space": "wide", "button.fg": "{color.missing}"}}); err != nil {
+		t.Fatalf("expected inherited checks to be deferred to lookup, got %v", err)
+	}
+	_, err = reg.Get("broken")
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected the flattened manifest to fail validation, got %v", err)
+	}
+	var codes []string
+	for _, issue := range validationErr.Issues {
+		codes = append(codes, issue.Path+":"+issue.Code)
+	}
+	if got := strings.Join(codes, ","); got != "tokens.button.fg:unknown_reference,tokens.space:invalid_value" {
+		t.Fatalf("unexpected issues: %s", got)
+	}
+}
+
+func TestRegistryExtendsParentVariantFallback(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "base", Version: "1.0.0", Variants: map[string]Variant{"dark": {Tokens: map[string]string{"bg": "black"}}}})
+	if err := reg.Register(&Manifest{
+		Name:     "brand",
+		Version:  "1.0.0",
+		Extends:  "base",
+		Variants: map[string]Variant{"high-contrast-dark": {Fallback: "dark", Tokens: map[string]string{"fg": "yellow"}}},
+	}); err != nil {
+		t.Fatalf("expected a fallback to an inherited variant to register, got %v", err)
+	}
+	brand, err := reg.Get("brand")
+	if err != nil {
+		t.Fatalf("get brand: %v", err)
+	}
+	if chain := strings.Join(brand.VariantChain("high-contrast-dark"), ","); chain != "high-contrast-dark,dark" {
+		t.Fatalf("expected chain through the inherited variant, got %s", chain)
+	}
+
+	reg.Register(&Manifest{Name: "broken", Version: "1.0.0", Extends: "base", Variants: map[string]Variant{"hc": {Fallback: "sepia"}}})
+	if _, err := reg.Get("broken"); !errors.As(err, new(ValidationError)) {
+		t.Fatalf("expected an unknown fallback to fail on the flattened manifest, got %v", err)
+	}
+}
+
+func TestRegistryExtendsParentAxisValues(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "base", Version: "1.0.0", Variants: map[string]Variant{"light": {}, "dark": {}}})
+	if err := reg.Register(&Manifest{
+		Name:    "brand",
+		Version: "1.0.0",
+		Extends: "base",
+		Axes:    []VariantAxis{{Name: "scheme", Values: []string{"light", "dark"}, Default: "light"}},
+	}); err != nil {
+		t.Fatalf("expected axis values naming inherited variants to register, got %v", err)
+	}
+	brand, err := reg.Get("brand")
+	if err != nil {
+		t.Fatalf("get brand: %v", err)
+	}
+	if axis, ok := brand.Axis("scheme"); !ok || len(axis.Values) != 2 {
+		t.Fatalf("expected the scheme axis on the flattened manifest, got %+v", brand.Axes)
+	}
+
+	reg.Register(&Manifest{Name: "broken", Version: "1.0.0", Extends: "base", Axes: []VariantAxis{{Name: "scheme", Values: []string{"sepia"}}}})
+	if _, err := reg.Get("broken"); !errors.As(err, new(ValidationError)) {
+		t.Fatalf("expected an unknown axis value to fail on the flattened manifest, got %v", err)
+	}
+}
+
+func TestRegistryExtendsCycle(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "a", Version: "1.0.0", Extends: "b", Tokens: map[string]string{"x": "1"}})
+	reg.Register(&Manifest{Name: "b", Version: "1.0.0", Extends: "a", Tokens: map[string]string{"x": "2"}})
+
+	if _, err := reg.Get("a"); !errors.Is(err, ErrExtendsCycle) {
+		t.Fatalf("expected ErrExtendsCycle, got %v", err)
+	}
+}
+
+func TestValidateRejectsExtendsWithoutName(t *testing.T) {
+	m := Manifest{Name: "child", Version: "1.0.0", Extends: "@1.0.0"}
+	if err := m.Validate(); err == nil {
+		t.Fatalf("expected validation error for extends without name")
+	}
+}
@@ -0,0 +1,101 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ProviderLayer is a labelled ThemeProvider within a LayeredProvider.
+type ProviderLayer struct {
+	Source   string
+	Provider ThemeProvider
+}
+
+// LayeredProvider combines ThemeProviders in priority order, first layer highest. A theme name found
+// in a layer shadows every version of that name in the layers below it.
+type LayeredProvider struct {
+	layers []ProviderLayer
+}
+
+// NewLayeredProvider builds a LayeredProvider; nil providers are skipped.
+func NewLayeredProvider(layers ...ProviderLayer) *LayeredProvider {
+	p := &LayeredProvider{}
+	for _, layer := range layers {
+		if layer.Provider != nil {
+			p.layers = append(p.layers, layer)
+		}
+	}
+	return p
+}
+
+// Theme returns the manifest from the first layer that knows the name. Only ErrThemeNotFound falls
+// through to the next layer; any other error (including ErrVersionNotFound) is returned as-is, so a
+// shadowing layer never silently serves a lower layer's version. Extends parents are looked up the same
+// way across all layers, so a tenant theme can extend a builtin one. Layers that ignore WithoutExtends
+// return their manifests already flattened within the layer.
+func (p *LayeredProvider) Theme(name string, opts ...QueryOption) (*Manifest, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	manifest, err := p.raw(name, append(opts[:len(opts):len(opts)], WithoutExtends()))
+	if err != nil {
+		return nil, err
+	}
+	if newQueryOptions(opts).raw {
+		return manifest, nil
+	}
+	return flattenManifest(manifest, func(parent, version string) (*Manifest, error) {
+		settings := parentQuery(version)
+		return p.raw(parent, []QueryOption{func(opts *queryOptions) { *opts = settings }, WithoutExtends()})
+	})
+}
+
+// raw returns the unflattened manifest from the first layer that knows the name.
+func (p *LayeredProvider) raw(name string, opts []QueryOption) (*Manifest, error) {
+	for _, layer := range p.layers {
+		manifest, err := layer.Provider.Theme(name, opts...)
+		if err == nil {
+			return manifest, nil
+		}
+		if !isThemeMissing(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+}
+
+// Themes merges the listings of every layer. Names listed by a higher layer hide the lower layers'
+// refs for that name; each ref's Source is set to its layer's label unless a nested provider set one.
+func (p *LayeredProvider) Themes() []ManifestRef {
+	shadowed := map[string]bool{}
+	var refs []ManifestRef
+	for _, layer := range p.layers {
+		names := map[string]bool{}
+		for _, ref := range layer.Provider.Themes() {
+			if shadowed[ref.Name] {
+				continue
+			}
+			names[ref.Name] = true
+			if ref.Source == "" {
+				ref.Source = layer.Source
+			}
+			refs = append(refs, ref)
+		}
+		for name := range names {
+			shadowed[name] = true
+		}
+	}
+	sortManifestRefs(refs)
+	return refs
+}
+
+// isThemeMissing reports a missing theme name, excluding a missing extends parent of a found theme.
+func isThemeMissing(err error) bool {
+	return errors.Is(err, ErrThemeNotFound) && !errors.Is(err, ErrParentNotFound)
+}
+
+// ThemesFiltered returns the page of the merged listing matching filter.
+func (p *LayeredProvider) ThemesFiltered(filter ListFilter) ManifestPage {
+	return filterManifestRefs(p.Themes(), filter)
+}
@@ -0,0 +1,90 @@
+package theme
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLayeredProviderShadowsByName(t *testing.T) {
+	builtin := NewRegistry()
+	builtin.Register(&Manifest{Name: "acme", Version: "1.0.0", Tokens: map[string]string{"primary": "blue"}})
+	builtin.Register(&Manifest{Name: "acme", Version: "2.0.0", Tokens: map[string]string{"primary": "navy"}})
+	builtin.Register(&Manifest{Name: "plain", Version: "1.0.0"})
+
+	tenant := NewRegistry()
+	tenant.Register(&Manifest{Name: "acme", Version: "1.5.0", Tokens: map[string]string{"primary": "red"}})
+	tenant.Register(&Manifest{Name: "custom", Version: "1.0.0"})
+
+	provider := NewLayeredProvider(
+		ProviderLayer{Source: "tenant", Provider: tenant},
+		ProviderLayer{Source: "builtin", Provider: builtin},
+	)
+
+	acme, err := provider.Theme("acme")
+	if err != nil || acme.Version != "1.5.0" {
+		t.Fatalf("expected tenant acme to shadow builtin, got %+v (%v)", acme, err)
+	}
+	if plain, err := provider.Theme("plain"); err != nil || plain.Name != "plain" {
+		t.Fatalf("expected fallthrough to builtin, got %+v (%v)", plain, err)
+	}
+	if _, err := provider.Theme("acme", WithVersion("2.0.0"), WithoutFallback()); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected shadowing layer to report ErrVersionNotFound, got %v", err)
+	}
+	if _, err := provider.Theme("missing"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected ErrThemeNotFound, got %v", err)
+	}
+
+	refs := provider.Themes()
+	want := []ManifestRef{
+		{Name: "acme", Version: "1.5.0", Source: "tenant"},
+		{Name: "custom", Version: "1.0.0", Source: "tenant"},
+		{Name: "plain", Version: "1.0.0", Source: "builtin"},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, refs)
+	}
+	for i := range want {
+		if refs[i].Name != want[i].Name || refs[i].Version != want[i].Version || refs[i].Source != want[i].Source || !refs[i].Latest {
+			t.Fatalf("ref %d: expected %+v, got %+v", i, want[i], refs[i])
+		}
+	}
+
+	sel, err := Selector{Registry: provider}.Select("acme", "")
+	if err != nil || sel.Tokens()["primary"] != "red" {
+		t.Fatalf("expected selector to use the layered provider, got %+v (%v)", sel, err)
+	}
+}
+
+func TestLayeredProviderSurfacesMissingParent(t *testing.T) {
+	tenant := NewRegistry()
+	tenant.Register(&Manifest{Name: "brand", Version: "1.0.0", Extends: "acme"})
+	builtin := NewRegistry()
+	builtin.Register(&Manifest{Name: "brand", Version: "1.0.0"})
+
+	provider := NewLayeredProvider(ProviderLayer{Source: "tenant", Provider: tenant}, ProviderLayer{Source: "builtin", Provider: builtin})
+	if _, err := provider.Theme("brand"); !errors.Is(err, ErrParentNotFound) {
+		t.Fatalf("expected ErrParentNotFound instead of falling through, got %v", err)
+	}
+}
+
+func TestLayeredProviderResolvesParentsAcrossLayers(t *testing.T) {
+	builtin := NewRegistry()
+	builtin.Register(&Manifest{Name: "builtin", Version: "1.0.0", Tokens: map[string]string{"primary": "blue", "radius": "4px"}})
+	builtin.Register(&Manifest{Name: "builtin", Version: "2.0.0", Tokens: map[string]string{"primary": "navy"}})
+	tenant := NewRegistry()
+	tenant.Register(&Manifest{Name: "brand", Version: "1.0.0", Extends: "builtin@1.0.0", Tokens: map[string]string{"primary": "red"}})
+
+	provider := NewLayeredProvider(ProviderLayer{Source: "tenant", Provider: tenant}, ProviderLayer{Source: "builtin", Provider: builtin})
+	brand, err := provider.Theme("brand")
+	if err != nil {
+		t.Fatalf("expected parent from a lower layer to resolve, got %v", err)
+	}
+	if brand.Tokens["primary"] != "red" || brand.Tokens["radius"] != "4px" || brand.Extends != "" {
+		t.Fatalf("expected flattened tenant theme, got %+v", brand)
+	}
+
+	raw, err := provider.Theme("brand", WithoutExtends())
+	if err != nil || raw.Extends != "builtin@1.0.0" || raw.Tokens["radius"] != "" {
+		t.Fatalf("expected WithoutExtends to return the stored manifest, got %+v (%v)", raw, err)
+	}
+}
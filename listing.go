@@ -0,0 +1,127 @@
+package theme
+
+import (
+	"sort"
+	"strings"
+)
+
+// ListFilter narrows a theme listing. Zero values match everything; Limit <= 0 returns all matches
+// after Offset.
+type ListFilter struct {
+	NamePrefix string
+	Tag        string
+	Variant    string
+	LatestOnly bool
+	Offset     int
+	Limit      int
+}
+
+// ManifestPage is one page of a filtered listing; Total counts every match before pagination.
+type ManifestPage struct {
+	Refs   []ManifestRef
+	Total  int
+	Offset int
+	Limit  int
+}
+
+// newManifestRef summarizes a (flattened) manifest.
+func newManifestRef(manifest *Manifest) ManifestRef {
+	ref := ManifestRef{
+		Name:          manifest.Name,
+		Version:       manifest.Version,
+		Description:   manifest.Description,
+		Author:        manifest.Author,
+		Tags:          cloneStrings(manifest.Tags),
+		Variants:      variantNames(manifest),
+		TokenCount:    len(manifest.Tokens),
+		TemplateCount: len(manifest.Templates),
+		AssetCount:    len(manifest.Assets.Files),
+	}
+	if preview := strings.TrimSpace(manifest.Preview); preview != "" {
+		ref.Preview, _ = resolveAsset(manifest, nil, preview)
+	}
+	return ref
+}
+
+// markLatest flags, per theme name, the version a plain lookup resolves to.
+func markLatest(refs []ManifestRef, stableOnly bool) {
+	versions := map[string][]string{}
+	for _, ref := range refs {
+		versions[ref.Name] = append(versions[ref.Name], ref.Version)
+	}
+	latest := make(map[string]string, len(versions))
+	for name, stored := range versions {
+		latest[name], _ = selectVersion(name, stored, queryOptions{}, stableOnly)
+	}
+	for i := range refs {
+		refs[i].Latest = refs[i].Version == latest[refs[i].Name]
+	}
+}
+
+// FilterThemes returns the page of provider's listing matching filter, delegating to providers that
+// implement FilteredThemeProvider and filtering Themes() otherwise.
+func FilterThemes(provider ThemeProvider, filter ListFilter) ManifestPage {
+	if filtered, ok := provider.(FilteredThemeProvider); ok {
+		return filtered.ThemesFiltered(filter)
+	}
+	return filterManifestRefs(provider.Themes(), filter)
+}
+
+// filterManifestRefs applies filter to refs (already sorted) and paginates the result.
+func filterManifestRefs(refs []ManifestRef, filter ListFilter) ManifestPage {
+	var matched []ManifestRef
+	for _, ref := range refs {
+		if filter.matches(ref) {
+			matched = append(matched, ref)
+		}
+	}
+
+	page := ManifestPage{Total: len(matched), Offset: filter.Offset, Limit: filter.Limit}
+	start := filter.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := len(matched)
+	if filter.Limit > 0 && start+filter.Limit < end {
+		end = start + filter.Limit
+	}
+	page.Refs = matched[start:end]
+	return page
+}
+
+func (f ListFilter) matches(ref ManifestRef) bool {
+	if f.NamePrefix != "" && !strings.HasPrefix(ref.Name, f.NamePrefix) {
+		return false
+	}
+	if f.LatestOnly && !ref.Latest {
+		return false
+	}
+	if f.Tag != "" && !containsString(ref.Tags, f.Tag) {
+		return false
+	}
+	if f.Variant != "" && !containsString(ref.Variants, f.Variant) {
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+func variantNames(manifest *Manifest) []string {
+	names := make([]string, 0, len(manifest.Variants))
+	for name := range manifest.Variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,114 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func listingRegistry() *MemoryRegistry {
+	reg := NewRegistry(WithStableLatest())
+	reg.Register(&Manifest{
+		Name:    "acme",
+		Version: "1.0.0",
+		Author:  "Acme Inc",
+		Tags:    []string{"corporate"},
+		Tokens:  map[string]string{"primary": "blue", "accent": "red"},
+		Assets: Assets{
+			Prefix: "/static/acme",
+			Files:  map[string]string{"preview": "preview.png", "logo": "logo.svg"},
+		},
+		Preview:   "preview",
+		Templates: map[string]string{"layout.header": "acme/header.tmpl"},
+		Variants:  map[string]Variant{"dark": {}, "light": {}},
+	})
+	reg.Register(&Manifest{Name: "acme", Version: "1.1.0", Tags: []string{"corporate"}, Variants: map[string]Variant{"light": {}}})
+	reg.Register(&Manifest{Name: "acme", Version: "2.0.0-beta.1", Tags: []string{"corporate", "beta"}})
+	reg.Register(&Manifest{Name: "acme-dark", Version: "1.0.0", Extends: "acme@1.0.0", Tags: []string{"dark"}})
+	reg.Register(&Manifest{Name: "brand", Version: "1.0.0"})
+	return reg
+}
+
+func TestListIncludesMetadata(t *testing.T) {
+	refs := listingRegistry().List()
+	if len(refs) != 5 {
+		t.Fatalf("expected 5 refs, got %+v", refs)
+	}
+
+	var acme, child ManifestRef
+	latest := map[string]string{}
+	for _, ref := range refs {
+		if ref.Name == "acme" && ref.Version == "1.0.0" {
+			acme = ref
+		}
+		if ref.Name == "acme-dark" {
+			child = ref
+		}
+		if ref.Latest {
+			latest[ref.Name] += ref.Version
+		}
+	}
+
+	if acme.Author != "Acme Inc" || acme.TokenCount != 2 || acme.TemplateCount != 1 || acme.AssetCount != 2 {
+		t.Fatalf("unexpected counts/author: %+v", acme)
+	}
+	if strings.Join(acme.Variants, ",") != "dark,light" || acme.Preview != "/static/acme/preview.png" {
+		t.Fatalf("unexpected variants/preview: %+v", acme)
+	}
+	if child.TokenCount != 2 || child.Preview != "/static/acme/preview.png" || child.Author != "Acme Inc" {
+		t.Fatalf("expected extends to be flattened into the ref, got %+v", child)
+	}
+	if latest["acme"] != "1.1.0" || latest["acme-dark"] != "1.0.0" || latest["brand"] != "1.0.0" {
+		t.Fatalf("expected latest flags to follow the stable policy, got %v", latest)
+	}
+}
+
+func TestListFiltered(t *testing.T) {
+	reg := listingRegistry()
+
+	versions := func(page ManifestPage) string {
+		var out []string
+		for _, ref := range page.Refs {
+			out = append(out, ref.Name+"@"+ref.Version)
+		}
+		return strings.Join(out, ",")
+	}
+
+	cases := []struct {
+		filter ListFilter
+		want   string
+		total  int
+	}{
+		{ListFilter{NamePrefix: "acme"}, "acme@2.0.0-beta.1,acme@1.1.0,acme@1.0.0,acme-dark@1.0.0", 4},
+		{ListFilter{Tag: "corporate", LatestOnly: true}, "acme@1.1.0", 1},
+		{ListFilter{Variant: "light"}, "acme@1.1.0,acme@1.0.0,acme-dark@1.0.0", 3},
+		{ListFilter{LatestOnly: true, Offset: 1, Limit: 1}, "acme-dark@1.0.0", 3},
+		{ListFilter{Offset: 10}, "", 5},
+	}
+	for _, tc := range cases {
+		page := reg.ListFiltered(tc.filter)
+		if got := versions(page); got != tc.want || page.Total != tc.total {
+			t.Fatalf("%+v: expected %q (total %d), got %q (total %d)", tc.filter, tc.want, tc.total, got, page.Total)
+		}
+	}
+
+	layered := NewLayeredProvider(ProviderLayer{Source: "builtin", Provider: reg})
+	if page := layered.ThemesFiltered(ListFilter{Tag: "dark"}); versions(page) != "acme-dark@1.0.0" || page.Refs[0].Source != "builtin" {
+		t.Fatalf("expected layered provider to filter, got %+v", page)
+	}
+
+	plain := &countingProvider{ThemeProvider: reg}
+	if page := FilterThemes(plain, ListFilter{Tag: "dark"}); versions(page) != "acme-dark@1.0.0" || plain.lists.Load() != 1 {
+		t.Fatalf("expected FilterThemes to filter a provider without ThemesFiltered, got %+v", page)
+	}
+	if page := FilterThemes(layered, ListFilter{Tag: "dark"}); page.Refs[0].Source != "builtin" {
+		t.Fatalf("expected FilterThemes to delegate to ThemesFiltered, got %+v", page)
+	}
+}
+
+func TestValidatePreviewAndTags(t *testing.T) {
+	m := &Manifest{Name: "acme", Version: "1.0.0", Preview: "missing", Tags: []string{"ok", " "}}
+	issues := m.ValidationIssues()
+	if len(issues) != 2 || issues[0].Path != "preview" || issues[0].Severity != SeverityWarning || issues[1].Path != "tags.1" {
+		t.Fatalf("unexpected issues: %+v", issues)
+	}
+}
@@ -1,12 +1,19 @@
 package theme
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"path"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,47 +21,157 @@ var defaultManifestNames = []string{
 	"theme.json",
 	"theme.yaml",
 	"theme.yml",
+	"theme.toml",
 	"manifest.json",
 	"manifest.yaml",
 	"manifest.yml",
+	"manifest.toml",
 }
 
-// LoadBytes parses a manifest from raw bytes. If format is empty, it will try JSON then YAML.
-func LoadBytes(data []byte, format string) (*Manifest, error) {
+// DecodeAttempt records why a single decoder rejected a manifest document.
+// Line and Column are 1-based and zero when unknown; Offset is the byte offset reported by the JSON decoder.
+type DecodeAttempt struct {
+	Format string
+	Line   int
+	Column int
+	Offset int64
+	Err    error
+}
+
+// Error implements the error interface.
+func (a *DecodeAttempt) Error() string {
+	msg := a.Err.Error()
+	if a.Line > 0 && !strings.Contains(msg, "line ") {
+		msg = fmt.Sprintf("line %d, column %d: %s", a.Line, a.Column, msg)
+	}
+	return fmt.Sprintf("%s decode: %s", a.Format, msg)
+}
+
+// Unwrap returns the underlying decoder error.
+func (a *DecodeAttempt) Unwrap() error {
+	return a.Err
+}
+
+// DecodeError is returned when a manifest cannot be decoded. When the format was auto-detected it
+// carries one attempt per decoder, the most likely format first.
+type DecodeError struct {
+	// Path is the manifest path when loaded through LoadFile/LoadDir.
+	Path string
+	// Format is the requested format, empty when auto-detected.
+	Format   string
+	Attempts []*DecodeAttempt
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	messages := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		messages[i] = attempt.Error()
+	}
+	msg := strings.Join(messages, "; ")
+	if len(e.Attempts) > 1 {
+		msg = "could not detect manifest format: " + msg
+	}
+	if e.Path != "" {
+		return fmt.Sprintf("decode manifest %s: %s", e.Path, msg)
+	}
+	return msg
+}
+
+// Unwrap exposes every decoder attempt to errors.Is/As.
+func (e *DecodeError) Unwrap() []error {
+	errs := make([]error, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		errs[i] = attempt
+	}
+	return errs
+}
+
+// LoadOptions configures manifest loading; the zero value decodes leniently without schema validation.
+type LoadOptions struct {
+	// Strict rejects fields that do not exist on Manifest, Assets or Variant instead of silently
+	// ignoring them. JSON errors include the line and column of the first unknown key, YAML errors
+	// the line of each; TOML errors list the dotted key of each unknown field.
+	Strict bool
+	// ValidateSchema validates the raw document against ManifestSchema before decoding,
+	// reporting unknown or mistyped fields as a ValidationError.
+	ValidateSchema bool
+}
+
+// mergeLoadOptions combines the optional LoadOptions arguments; any enabled setting wins.
+func mergeLoadOptions(opts []LoadOptions) LoadOptions {
+	var settings LoadOptions
+	for _, opt := range opts {
+		settings.Strict = settings.Strict || opt.Strict
+		settings.ValidateSchema = settings.ValidateSchema || opt.ValidateSchema
+	}
+	return settings
+}
+
+// LoadBytes parses a JSON, YAML or TOML manifest from raw bytes. If format is empty, the content is sniffed
+// (a leading "{" means JSON, a "key = value" or "[table]" line means TOML) and the other decoders are tried next;
+// when none succeeds a *DecodeError carries every attempt.
+func LoadBytes(data []byte, format string, opts ...LoadOptions) (*Manifest, error) {
+	settings := mergeLoadOptions(opts)
 	format = normalizeFormat(format)
 
 	switch format {
-	case "json":
-		return decodeJSON(data)
-	case "yaml":
-		return decodeYAML(data)
+	case "json", "yaml", "toml":
+		manifest, err := decodeFormat(data, format, settings)
+		var attempt *DecodeAttempt
+		if errors.As(err, &attempt) {
+			return nil, &DecodeError{Format: format, Attempts: []*DecodeAttempt{attempt}}
+		}
+		return manifest, err
 	case "":
-		if manifest, err := decodeJSON(data); err == nil {
-			return manifest, nil
+		decodeErr := &DecodeError{}
+		for _, candidate := range sniffFormats(data) {
+			manifest, err := decodeFormat(data, candidate, settings)
+			if err == nil {
+				return manifest, nil
+			}
+			var attempt *DecodeAttempt
+			if !errors.As(err, &attempt) {
+				return nil, err
+			}
+			decodeErr.Attempts = append(decodeErr.Attempts, attempt)
 		}
-		return decodeYAML(data)
+		return nil, decodeErr
 	default:
 		return nil, fmt.Errorf("unsupported manifest format: %s", format)
 	}
 }
 
 // LoadFile reads a manifest from a given fs.FS path, inferring format from the extension.
-func LoadFile(fsys fs.FS, manifestPath string) (*Manifest, error) {
+func LoadFile(fsys fs.FS, manifestPath string, opts ...LoadOptions) (*Manifest, error) {
 	data, err := fs.ReadFile(fsys, manifestPath)
 	if err != nil {
 		return nil, fmt.Errorf("read manifest: %w", err)
 	}
 
 	format := normalizeFormat(strings.TrimPrefix(path.Ext(manifestPath), "."))
-	manifest, err := LoadBytes(data, format)
+	manifest, err := LoadBytes(data, format, opts...)
 	if err != nil {
+		var decodeErr *DecodeError
+		if errors.As(err, &decodeErr) {
+			decodeErr.Path = manifestPath
+			return nil, decodeErr
+		}
 		return nil, fmt.Errorf("decode manifest %s: %w", manifestPath, err)
 	}
 	return manifest, nil
 }
 
 // LoadDir searches common manifest filenames within a directory in the provided fs.FS.
-func LoadDir(fsys fs.FS, dir string) (*Manifest, error) {
+func LoadDir(fsys fs.FS, dir string, opts ...LoadOptions) (*Manifest, error) {
+	if candidate, ok := findManifest(fsys, dir); ok {
+		return LoadFile(fsys, candidate, opts...)
+	}
+	return nil, fmt.Errorf("no manifest found in %s (looked for %s)", dir, strings.Join(defaultManifestNames, ", "))
+}
+
+// findManifest returns the first defaultManifestNames entry that exists as a file in dir.
+func findManifest(fsys fs.FS, dir string) (string, bool) {
 	for _, name := range defaultManifestNames {
 		candidate := path.Join(dir, name)
 		info, err := fs.Stat(fsys, candidate)
@@ -64,9 +181,9 @@ func LoadDir(fsys fs.FS, dir string) (*Manifest, error) {
 		if info.IsDir() {
 			continue
 		}
-		return LoadFile(fsys, candidate)
+		return candidate, true
 	}
-	return nil, fmt.Errorf("no manifest found in %s (looked for %s)", dir, strings.Join(defaultManifestNames, ", "))
+	return "", false
 }
 
 func normalizeFormat(format string) string {
@@ -75,15 +192,102 @@ func normalizeFormat(format string) string {
 		return "json"
 	case "yaml", "yml":
 		return "yaml"
+	case "toml":
+		return "toml"
 	default:
 		return ""
 	}
 }
 
-func decodeJSON(data []byte) (*Manifest, error) {
+var tomlLinePattern = regexp.MustCompile(`^(\[\[?\s*[A-Za-z0-9_."'-][^\]]*\]\]?|[A-Za-z0-9_."'-]+\s*=)`)
+
+// sniffFormats orders decoders by what the content looks like: TOML when the first significant line is a
+// "[table]" header or "key = value" pair (TOML is only tried then), JSON first for a leading "{" or "[".
+func sniffFormats(data []byte) []string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if looksLikeTOML(trimmed) {
+		return []string{"toml", "yaml", "json"}
+	}
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return []string{"json", "yaml"}
+	}
+	return []string{"yaml", "json"}
+}
+
+func looksLikeTOML(data []byte) bool {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		return tomlLinePattern.Match(line)
+	}
+	return false
+}
+
+func decodeFormat(data []byte, format string, settings LoadOptions) (*Manifest, error) {
+	switch format {
+	case "json":
+		return decodeJSON(data, settings)
+	case "toml":
+		return decodeTOML(data, settings)
+	default:
+		return decodeYAML(data, settings)
+	}
+}
+
+func decodeJSON(data []byte, settings LoadOptions) (*Manifest, error) {
+	if settings.ValidateSchema {
+		var raw any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, jsonDecodeAttempt(data, err)
+		}
+		if err := validateSchema(raw, false); err != nil {
+			return nil, err
+		}
+	}
+
+	var manifest Manifest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if settings.Strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&manifest); err != nil {
+		attempt := jsonDecodeAttempt(data, err)
+		if settings.Strict && attempt.Offset == 0 {
+			// DisallowUnknownFields errors carry no position; find the first unknown key in document order.
+			if offset, ok := jsonUnknownFieldOffset(data); ok {
+				attempt.Offset = offset + 1
+				attempt.Line, attempt.Column = lineColumn(data, offset)
+			}
+		}
+		return nil, attempt
+	}
+	if err := jsonTrailingData(decoder, data); err != nil {
+		return nil, err
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func decodeYAML(data []byte, settings LoadOptions) (*Manifest, error) {
+	if settings.ValidateSchema {
+		var raw any
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, yamlDecodeAttempt(err)
+		}
+		if err := validateSchema(raw, true); err != nil {
+			return nil, err
+		}
+	}
+
 	var manifest Manifest
-	if err := json.Unmarshal(data, &manifest); err != nil {
-		return nil, fmt.Errorf("json decode: %w", err)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(settings.Strict)
+	if err := decoder.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, yamlDecodeAttempt(err)
 	}
 	if err := manifest.Validate(); err != nil {
 		return nil, err
@@ -91,13 +295,243 @@ func decodeJSON(data []byte) (*Manifest, error) {
 	return &manifest, nil
 }
 
-func decodeYAML(data []byte) (*Manifest, error) {
+func decodeTOML(data []byte, settings LoadOptions) (*Manifest, error) {
+	if settings.ValidateSchema {
+		var raw map[string]any
+		if _, err := toml.Decode(string(data), &raw); err != nil {
+			return nil, tomlDecodeAttempt(data, err)
+		}
+		if err := validateSchema(raw, false); err != nil {
+			return nil, err
+		}
+	}
+
 	var manifest Manifest
-	if err := yaml.Unmarshal(data, &manifest); err != nil {
-		return nil, fmt.Errorf("yaml decode: %w", err)
+	meta, err := toml.Decode(string(data), &manifest)
+	if err != nil {
+		return nil, tomlDecodeAttempt(data, err)
+	}
+	if settings.Strict {
+		if unknown := tomlUnknownFields(meta); len(unknown) > 0 {
+			return nil, tomlDecodeAttempt(data, errors.New(strings.Join(unknown, "; ")))
+		}
 	}
 	if err := manifest.Validate(); err != nil {
 		return nil, err
 	}
 	return &manifest, nil
 }
+
+// tomlUnknownFields maps undecoded keys back onto Manifest and reports the outermost unknown field of each,
+// so children of an unknown table are not repeated.
+func tomlUnknownFields(meta toml.MetaData) []string {
+	reported := map[string]bool{}
+	var unknown []string
+	for _, key := range meta.Undecoded() {
+		t := reflect.TypeOf(Manifest{})
+		for i, segment := range key {
+			if t.Kind() == reflect.Map {
+				t = t.Elem()
+				continue
+			}
+			if t.Kind() == reflect.Slice {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.Struct {
+				break
+			}
+			fieldType, ok := taggedFields(t, "toml")[segment]
+			if !ok {
+				label := "manifest"
+				if i > 0 {
+					label = key[:i].String()
+				}
+				if id := key[:i+1].String(); !reported[id] {
+					reported[id] = true
+					unknown = append(unknown, fmt.Sprintf("unknown field %q in %s", segment, label))
+				}
+				break
+			}
+			t = fieldType
+		}
+	}
+	return unknown
+}
+
+// taggedFields maps the struct tag names (e.g. "toml") of t's exported fields to their types.
+func taggedFields(t reflect.Type, tag string) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+)(?:, column (\d+))?`)
+
+func jsonDecodeAttempt(data []byte, err error) *DecodeAttempt {
+	attempt := &DecodeAttempt{Format: "json", Err: err}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		attempt.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		attempt.Offset = typeErr.Offset
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		attempt.Offset = int64(len(data))
+	}
+
+	if attempt.Offset > 0 {
+		attempt.Line, attempt.Column = lineColumn(data, attempt.Offset-1)
+	}
+	return attempt
+}
+
+// jsonUnknownFieldOffset walks the JSON tokens against Manifest and returns the byte offset of the first
+// object key that matches no json field, mirroring encoding/json's case-insensitive field matching.
+func jsonUnknownFieldOffset(data []byte) (int64, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	offset, found, err := walkJSONValue(decoder, data, reflect.TypeOf(Manifest{}))
+	return offset, found && err == nil
+}
+
+// walkJSONValue consumes one value; t is nil for values whose fields are not checked.
+func walkJSONValue(decoder *json.Decoder, data []byte, t reflect.Type) (int64, bool, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return 0, false, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return 0, false, nil
+	}
+
+	switch delim {
+	case '{':
+		for decoder.More() {
+			keyOffset := skipJSONSeparators(data, decoder.InputOffset())
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return 0, false, err
+			}
+			key, _ := keyToken.(string)
+
+			var valueType reflect.Type
+			switch {
+			case t == nil:
+			case t.Kind() == reflect.Map:
+				valueType = t.Elem()
+			case t.Kind() == reflect.Struct:
+				fieldType, ok := jsonField(t, key)
+				if !ok {
+					return keyOffset, true, nil
+				}
+				valueType = fieldType
+			}
+			if offset, found, err := walkJSONValue(decoder, data, valueType); found || err != nil {
+				return offset, found, err
+			}
+		}
+	case '[':
+		var elemType reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elemType = t.Elem()
+		}
+		for decoder.More() {
+			if offset, found, err := walkJSONValue(decoder, data, elemType); found || err != nil {
+				return offset, found, err
+			}
+		}
+	}
+
+	_, err = decoder.Token() // closing delimiter
+	return 0, false, err
+}
+
+// jsonField looks up a struct field by json name, preferring an exact match like encoding/json.
+func jsonField(t reflect.Type, key string) (reflect.Type, bool) {
+	fields := taggedFields(t, "json")
+	if fieldType, ok := fields[key]; ok {
+		return fieldType, true
+	}
+	for name, fieldType := range fields {
+		if strings.EqualFold(name, key) {
+			return fieldType, true
+		}
+	}
+	return nil, false
+}
+
+// skipJSONSeparators advances offset past whitespace and value separators.
+func skipJSONSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+		offset++
+	}
+	return offset
+}
+
+// jsonTrailingData rejects anything but whitespace after the decoded top-level value, as json.Unmarshal does.
+func jsonTrailingData(decoder *json.Decoder, data []byte) error {
+	offset := decoder.InputOffset()
+	if _, err := decoder.Token(); errors.Is(err, io.EOF) {
+		return nil
+	}
+	offset = skipJSONSeparators(data, offset)
+	attempt := &DecodeAttempt{Format: "json", Err: errors.New("invalid character after top-level value"), Offset: offset + 1}
+	attempt.Line, attempt.Column = lineColumn(data, offset)
+	return attempt
+}
+
+func yamlDecodeAttempt(err error) *DecodeAttempt {
+	attempt := &DecodeAttempt{Format: "yaml", Err: err}
+	if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+		attempt.Line, _ = strconv.Atoi(match[1])
+		attempt.Column, _ = strconv.Atoi(match[2])
+	}
+	return attempt
+}
+
+func tomlDecodeAttempt(data []byte, err error) *DecodeAttempt {
+	attempt := &DecodeAttempt{Format: "toml", Err: err}
+
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) {
+		attempt.Offset = int64(parseErr.Position.Start) + 1
+		attempt.Line, attempt.Column = lineColumn(data, int64(parseErr.Position.Start))
+	}
+	return attempt
+}
+
+// lineColumn converts a byte offset into 1-based line and column numbers.
+func lineColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, column := 1, 1
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+	return line, column
+}
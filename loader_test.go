@@ -1,8 +1,11 @@
 package theme
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"testing/fstest"
 )
@@ -84,3 +87,244 @@ func TestLoadBytesFailsValidation(t *testing.T) {
 		t.Fatalf("expected validation error")
 	}
 }
+
+func TestLoadBytesStrictYAMLReportsUnknownFields(t *testing.T) {
+	data := []byte(`name: default
+version: 1.0.0
+variant:
+  dark:
+    tokens:
+      primary: black
+assets:
+  prefx: /static
+`)
+
+	if _, err := LoadBytes(data, "yaml"); err != nil {
+		t.Fatalf("expected lenient decoding by default, got %v", err)
+	}
+
+	_, err := LoadBytes(data, "yaml", LoadOptions{Strict: true})
+	if err == nil {
+		t.Fatalf("expected strict decoding error")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		"line 3: field variant not found in type theme.Manifest",
+		"line 8: field prefx not found in type theme.Assets",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in %q", want, msg)
+		}
+	}
+}
+
+func TestLoadBytesStrictJSONReportsUnknownFields(t *testing.T) {
+	data := []byte("{\n  \"name\": \"default\",\n  \"version\": \"1.0.0\",\n  \"variant\": {}\n}")
+
+	_, err := LoadBytes(data, "json", LoadOptions{Strict: true})
+	if err == nil {
+		t.Fatalf("expected strict decoding error")
+	}
+	if !strings.Contains(err.Error(), `line 4, column 3: json: unknown field "variant"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadBytesStrictJSONLocatesUnknownFieldByStructure(t *testing.T) {
+	data := []byte("{\"name\": \"a\", \"description\": \"\\\"variant\\\": {}\", \"tokens\": {\"variant\": \"x\"},\n \"version\": \"1.0.0\", \"variant\": {}}")
+
+	_, err := LoadBytes(data, "json", LoadOptions{Strict: true})
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if attempt := decodeErr.Attempts[0]; attempt.Line != 2 || attempt.Column != 22 {
+		t.Fatalf("expected unknown field at line 2, column 22, got %+v", attempt)
+	}
+}
+
+func TestLoadBytesJSONRejectsTrailingData(t *testing.T) {
+	data := []byte("{\"name\": \"a\", \"version\": \"1.0.0\"}\n  garbage")
+
+	_, err := LoadBytes(data, "json")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError for trailing data, got %v", err)
+	}
+	if attempt := decodeErr.Attempts[0]; attempt.Line != 2 || attempt.Column != 3 {
+		t.Fatalf("expected trailing data at line 2, column 3, got %+v", attempt)
+	}
+
+	if _, err := LoadBytes([]byte("{\"name\": \"a\", \"version\": \"1.0.0\"}\n\n"), "json"); err != nil {
+		t.Fatalf("expected trailing whitespace to be accepted, got %v", err)
+	}
+}
+
+func TestLoadDirStrictDecoding(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/acme/theme.yaml": &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\ntokns:\n  primary: blue\n")},
+	}
+
+	if _, err := LoadDir(fsys, "themes/acme", LoadOptions{Strict: true}); err == nil || !strings.Contains(err.Error(), "themes/acme/theme.yaml") {
+		t.Fatalf("expected strict error mentioning manifest path, got %v", err)
+	}
+}
+
+func TestLoadBytesAutoDetectReportsBothDecoders(t *testing.T) {
+	data := []byte("{\n  \"name\": \"default\",\n  \"version\": \"1.0.0\",\n  \"tokens\": {\"primary\": \"blue\" \"accent\": \"red\"}\n}")
+
+	_, err := LoadBytes(data, "")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError, got %T: %v", err, err)
+	}
+	if len(decodeErr.Attempts) != 2 {
+		t.Fatalf("expected json and yaml attempts, got %+v", decodeErr.Attempts)
+	}
+
+	jsonAttempt := decodeErr.Attempts[0]
+	if jsonAttempt.Format != "json" || jsonAttempt.Line != 4 || jsonAttempt.Column != 32 {
+		t.Fatalf("expected sniffed json attempt at line 4 column 32, got %+v", jsonAttempt)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected json syntax error to be reachable via errors.As")
+	}
+	if decodeErr.Attempts[1].Format != "yaml" {
+		t.Fatalf("expected yaml fallback attempt, got %+v", decodeErr.Attempts[1])
+	}
+	if !strings.HasPrefix(err.Error(), "could not detect manifest format: json decode: line 4, column 32:") {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
+
+func TestLoadBytesAutoDetectYAMLFirst(t *testing.T) {
+	_, err := LoadBytes([]byte("name: default\nversion: [1.0.0\n"), "")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if decodeErr.Attempts[0].Format != "yaml" || decodeErr.Attempts[0].Line == 0 {
+		t.Fatalf("expected yaml attempt with line number first, got %+v", decodeErr.Attempts[0])
+	}
+}
+
+func TestLoadFileDecodeErrorIncludesPath(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/acme/theme.json": &fstest.MapFile{Data: []byte(`{"name": "acme",`)},
+	}
+
+	_, err := LoadFile(fsys, "themes/acme/theme.json")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if decodeErr.Path != "themes/acme/theme.json" || decodeErr.Format != "json" {
+		t.Fatalf("expected path and format on decode error, got %+v", decodeErr)
+	}
+	if !strings.HasPrefix(err.Error(), "decode manifest themes/acme/theme.json: json decode: line 1") {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
+
+func TestLoadBytesTOML(t *testing.T) {
+	data := []byte(`name = "default"
+version = "1.0.0"
+
+[tokens]
+primary = "blue"
+"color.accent" = "{primary}"
+
+[assets]
+prefix = "/static"
+
+[assets.files]
+logo = "logo.png"
+
+[variants.dark.tokens]
+primary = "black"
+`)
+
+	for _, format := range []string{"toml", ""} {
+		manifest, err := LoadBytes(data, format)
+		if err != nil {
+			t.Fatalf("format %q: unexpected error: %v", format, err)
+		}
+		if manifest.Tokens["color.accent"] != "{primary}" || manifest.Assets.Files["logo"] != "logo.png" {
+			t.Fatalf("format %q: manifest not decoded correctly: %+v", format, manifest)
+		}
+		if manifest.TokensForVariant("dark")["color.accent"] != "black" {
+			t.Fatalf("format %q: expected variant tokens to be decoded, got %+v", format, manifest.Variants)
+		}
+	}
+}
+
+func TestLoadBytesTOMLStrictAndSchema(t *testing.T) {
+	data := []byte("name = \"default\"\nversion = \"1.0.0\"\n\n[variant.dark.tokens]\nprimary = \"black\"\n\n[assets]\nprefx = \"/static\"\n")
+
+	if _, err := LoadBytes(data, "toml"); err != nil {
+		t.Fatalf("expected lenient decoding by default, got %v", err)
+	}
+
+	_, err := LoadBytes(data, "toml", LoadOptions{Strict: true})
+	if err == nil {
+		t.Fatalf("expected strict decoding error")
+	}
+	for _, want := range []string{`unknown field "variant" in manifest`, `unknown field "prefx" in assets`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %q", want, err.Error())
+		}
+	}
+	if strings.Contains(err.Error(), `"dark"`) {
+		t.Fatalf("expected children of unknown tables to be skipped: %v", err)
+	}
+
+	_, err = LoadBytes(data, "toml", LoadOptions{ValidateSchema: true})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected schema ValidationError, got %v", err)
+	}
+}
+
+func TestLoadBytesTOMLStrictAxes(t *testing.T) {
+	data := []byte("name = \"default\"\nversion = \"1.0.0\"\n\n[variants.dark]\n\n[[axes]]\nname = \"scheme\"\nvalues = [\"dark\"]\ndefualt = \"dark\"\n")
+
+	_, err := LoadBytes(data, "toml", LoadOptions{Strict: true})
+	if err == nil || !strings.Contains(err.Error(), `unknown field "defualt" in axes`) {
+		t.Fatalf("expected unknown axis field error, got %v", err)
+	}
+
+	fixed := []byte(strings.Replace(string(data), "defualt", "default", 1))
+	manifest, err := LoadBytes(fixed, "toml", LoadOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(manifest.Axes) != 1 || manifest.Axes[0].Default != "dark" {
+		t.Fatalf("expected axes to be decoded, got %+v", manifest.Axes)
+	}
+}
+
+func TestLoadBytesTOMLDecodeErrorPosition(t *testing.T) {
+	_, err := LoadBytes([]byte("name = \"default\"\nversion = 1.0.0\n"), "toml")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if attempt := decodeErr.Attempts[0]; attempt.Format != "toml" || attempt.Line != 2 {
+		t.Fatalf("expected toml attempt on line 2, got %+v", attempt)
+	}
+}
+
+func TestLoadDirFindsTOML(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/acme/theme.toml": &fstest.MapFile{Data: []byte("name = \"acme\"\nversion = \"1.0.0\"\n")},
+	}
+
+	manifest, err := LoadDir(fsys, "themes/acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.Name != "acme" {
+		t.Fatalf("expected toml manifest to be loaded, got %+v", manifest)
+	}
+}
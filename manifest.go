@@ -3,111 +3,392 @@ package theme
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
 // Manifest defines the shape of a theme file that downstream systems (go-cms, go-formgen) can consume.
+// Preview names the assets.files key of an image shown by theme pickers.
+// Axes declares independent variant dimensions; overrides merge in axis order, later axes winning.
 type Manifest struct {
-	Name        string             `json:"name" yaml:"name"`
-	Version     string             `json:"version" yaml:"version"`
-	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
-	Tokens      map[string]string  `json:"tokens,omitempty" yaml:"tokens,omitempty"`
-	Fonts       map[string]string  `json:"fonts,omitempty" yaml:"fonts,omitempty"`
-	Assets      Assets             `json:"assets,omitempty" yaml:"assets,omitempty"`
-	Templates   map[string]string  `json:"templates,omitempty" yaml:"templates,omitempty"`
-	Variants    map[string]Variant `json:"variants,omitempty" yaml:"variants,omitempty"`
+	Name        string             `json:"name" yaml:"name" toml:"name"`
+	Version     string             `json:"version" yaml:"version" toml:"version"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Author      string             `json:"author,omitempty" yaml:"author,omitempty" toml:"author,omitempty"`
+	Tags        []string           `json:"tags,omitempty" yaml:"tags,omitempty" toml:"tags,omitempty"`
+	Extends     string             `json:"extends,omitempty" yaml:"extends,omitempty" toml:"extends,omitempty"`
+	Tokens      map[string]string  `json:"tokens,omitempty" yaml:"tokens,omitempty" toml:"tokens,omitempty"`
+	TokenTypes  map[string]string  `json:"token_types,omitempty" yaml:"token_types,omitempty" toml:"token_types,omitempty"`
+	Fonts       map[string]string  `json:"fonts,omitempty" yaml:"fonts,omitempty" toml:"fonts,omitempty"`
+	Assets      Assets             `json:"assets,omitempty" yaml:"assets,omitempty" toml:"assets,omitempty"`
+	Preview     string             `json:"preview,omitempty" yaml:"preview,omitempty" toml:"preview,omitempty"`
+	Templates   map[string]string  `json:"templates,omitempty" yaml:"templates,omitempty" toml:"templates,omitempty"`
+	Variants    map[string]Variant `json:"variants,omitempty" yaml:"variants,omitempty" toml:"variants,omitempty"`
+	Axes        []VariantAxis      `json:"axes,omitempty" yaml:"axes,omitempty" toml:"axes,omitempty"`
 }
 
 // Assets groups static assets and optional prefix/CDN root.
 type Assets struct {
-	Prefix string            `json:"prefix,omitempty" yaml:"prefix,omitempty"`
-	Files  map[string]string `json:"files,omitempty" yaml:"files,omitempty"`
+	Prefix string            `json:"prefix,omitempty" yaml:"prefix,omitempty" toml:"prefix,omitempty"`
+	Files  map[string]string `json:"files,omitempty" yaml:"files,omitempty" toml:"files,omitempty"`
 }
 
 // Variant captures token/template/asset overrides for a named variant (e.g., light/dark).
+// Fallback names another variant whose overrides apply underneath this one before base values.
 type Variant struct {
-	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
-	Tokens      map[string]string `json:"tokens,omitempty" yaml:"tokens,omitempty"`
-	Templates   map[string]string `json:"templates,omitempty" yaml:"templates,omitempty"`
-	Assets      Assets            `json:"assets,omitempty" yaml:"assets,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty" toml:"description,omitempty"`
+	Fallback    string            `json:"fallback,omitempty" yaml:"fallback,omitempty" toml:"fallback,omitempty"`
+	Tokens      map[string]string `json:"tokens,omitempty" yaml:"tokens,omitempty" toml:"tokens,omitempty"`
+	Templates   map[string]string `json:"templates,omitempty" yaml:"templates,omitempty" toml:"templates,omitempty"`
+	Assets      Assets            `json:"assets,omitempty" yaml:"assets,omitempty" toml:"assets,omitempty"`
+}
+
+// VariantAxis groups variants that are mutually exclusive along one dimension (e.g. color scheme,
+// density, contrast). Values name variants; Default is used when a selection leaves the axis unset.
+type VariantAxis struct {
+	Name    string   `json:"name" yaml:"name" toml:"name"`
+	Values  []string `json:"values" yaml:"values" toml:"values"`
+	Default string   `json:"default,omitempty" yaml:"default,omitempty" toml:"default,omitempty"`
+}
+
+// IssueSeverity classifies a validation issue; only errors make Validate fail.
+type IssueSeverity string
+
+const (
+	SeverityError   IssueSeverity = "error"
+	SeverityWarning IssueSeverity = "warning"
+)
+
+// Machine-readable validation issue codes.
+const (
+	IssueRequired         = "required"
+	IssueEmptyKey         = "empty_key"
+	IssueEmptyValue       = "empty_value"
+	IssueInvalidExtends   = "invalid_extends"
+	IssueUnknownReference = "unknown_reference"
+	IssueReferenceCycle   = "reference_cycle"
+	IssueUnknownType      = "unknown_type"
+	IssueUnusedType       = "unused_type"
+	IssueInvalidValue     = "invalid_value"
+	IssueInvalidVersion   = "invalid_version"
+	IssueUnknownAsset     = "unknown_asset"
+	IssueUnknownVariant   = "unknown_variant"
+)
+
+// ValidationIssue describes a single manifest problem located by a dotted field path
+// (e.g. "variants.dark.tokens.primary").
+type ValidationIssue struct {
+	Path     string        `json:"path"`
+	Code     string        `json:"code"`
+	Severity IssueSeverity `json:"severity"`
+	Message  string        `json:"message"`
+}
+
+// String returns the human readable issue message.
+func (i ValidationIssue) String() string {
+	return i.Message
 }
 
 // ValidationError aggregates manifest validation issues.
 type ValidationError struct {
-	Issues []string
+	Issues []ValidationIssue
 }
 
-// Error implements the error interface.
+// Error implements the error interface. Only error-severity issues are included; warnings are
+// available through Issues.
 func (e ValidationError) Error() string {
-	return fmt.Sprintf("manifest validation failed: %s", strings.Join(e.Issues, "; "))
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		if issue.Severity == SeverityWarning {
+			continue
+		}
+		messages = append(messages, issue.Message)
+	}
+	return fmt.Sprintf("manifest validation failed: %s", strings.Join(messages, "; "))
 }
 
 // Validate checks required fields and basic integrity for maps/variants.
+// It fails with a ValidationError when at least one issue has SeverityError; warnings are included alongside.
 func (m *Manifest) Validate() error {
 	if m == nil {
 		return fmt.Errorf("manifest is nil")
 	}
 
-	var issues []string
+	issues := m.ValidationIssues()
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return ValidationError{Issues: issues}
+		}
+	}
+	return nil
+}
+
+// ValidationIssues returns every error and warning for the manifest, sorted by path and code.
+// Checks that depend on inherited values (variant fallbacks, axis values, token references and token
+// types) are skipped while Extends is set; registries run them on the flattened manifest on lookup.
+func (m *Manifest) ValidationIssues() []ValidationIssue {
+	if m == nil {
+		return []ValidationIssue{{Code: IssueRequired, Severity: SeverityError, Message: "manifest is nil"}}
+	}
+
+	var issues []ValidationIssue
+	report := func(path, code, message string) {
+		issues = append(issues, ValidationIssue{Path: path, Code: code, Severity: SeverityError, Message: message})
+	}
 
 	if strings.TrimSpace(m.Name) == "" {
-		issues = append(issues, "name is required")
+		report("name", IssueRequired, "name is required")
 	}
 
 	if strings.TrimSpace(m.Version) == "" {
-		issues = append(issues, "version is required")
+		report("version", IssueRequired, "version is required")
+	} else if _, err := parseSemVer(m.Version); err != nil {
+		report("version", IssueInvalidVersion, fmt.Sprintf("version '%s' is not valid semver: %v", m.Version, err))
+	}
+
+	inherits := strings.TrimSpace(m.Extends) != ""
+	if inherits {
+		if name, _ := parseExtends(m.Extends); name == "" {
+			report("extends", IssueInvalidExtends, fmt.Sprintf("extends '%s' is missing a theme name", m.Extends))
+		}
 	}
 
 	validateMap := func(label string, values map[string]string) {
 		for k, v := range values {
 			if strings.TrimSpace(k) == "" {
-				issues = append(issues, fmt.Sprintf("%s has empty key", label))
+				report(label, IssueEmptyKey, fmt.Sprintf("%s has empty key", label))
 			}
 			if strings.TrimSpace(v) == "" {
-				issues = append(issues, fmt.Sprintf("%s entry '%s' is empty", label, k))
+				report(label+"."+k, IssueEmptyValue, fmt.Sprintf("%s entry '%s' is empty", label, k))
 			}
 		}
 	}
 
+	for i, tag := range m.Tags {
+		if strings.TrimSpace(tag) == "" {
+			report(fmt.Sprintf("tags.%d", i), IssueEmptyValue, fmt.Sprintf("tags entry %d is empty", i))
+		}
+	}
+
+	if preview := strings.TrimSpace(m.Preview); preview != "" {
+		if _, ok := m.Assets.Files[preview]; !ok {
+			issues = append(issues, ValidationIssue{
+				Path:     "preview",
+				Code:     IssueUnknownAsset,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("preview '%s' does not match any assets.files entry", m.Preview),
+			})
+		}
+	}
+
 	validateMap("tokens", m.Tokens)
+	validateMap("token_types", m.TokenTypes)
 	validateMap("fonts", m.Fonts)
 	validateMap("templates", m.Templates)
 	validateMap("assets.files", m.Assets.Files)
 
 	for name, variant := range m.Variants {
 		if strings.TrimSpace(name) == "" {
-			issues = append(issues, "variant name cannot be empty")
+			report("variants", IssueEmptyKey, "variant name cannot be empty")
+		}
+		if fallback := strings.TrimSpace(variant.Fallback); fallback != "" && !inherits {
+			if _, ok := m.Variants[fallback]; !ok {
+				report(fmt.Sprintf("variants.%s.fallback", name), IssueUnknownVariant, fmt.Sprintf("variant '%s' falls back to unknown variant '%s'", name, variant.Fallback))
+			} else if m.variantFallbackCycle(name) {
+				report(fmt.Sprintf("variants.%s.fallback", name), IssueReferenceCycle, fmt.Sprintf("variant '%s' has a fallback cycle through '%s'", name, variant.Fallback))
+			}
 		}
 		validateMap(fmt.Sprintf("variants.%s.tokens", name), variant.Tokens)
 		validateMap(fmt.Sprintf("variants.%s.templates", name), variant.Templates)
 		validateMap(fmt.Sprintf("variants.%s.assets.files", name), variant.Assets.Files)
 	}
 
-	if len(issues) > 0 {
-		return ValidationError{Issues: issues}
+	issues = append(issues, m.axisIssues(inherits)...)
+
+	for _, key := range sortedKeys(m.TokenTypes) {
+		tokenType := m.TokenTypes[key]
+		if _, ok := tokenTypeValidators[TokenType(tokenType)]; !ok && strings.TrimSpace(tokenType) != "" {
+			report("token_types."+key, IssueUnknownType, fmt.Sprintf("token_types entry '%s' has unknown type '%s'", key, tokenType))
+		}
+		if !inherits && !m.definesToken(key) {
+			issues = append(issues, ValidationIssue{
+				Path:     "token_types." + key,
+				Code:     IssueUnusedType,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("token_types entry '%s' does not match any token", key),
+			})
+		}
 	}
 
-	return nil
+	if inherits {
+		sortValidationIssues(issues)
+		return issues
+	}
+
+	resolved, refIssues := resolveTokenReferences(m.Tokens)
+	for _, issue := range refIssues {
+		issues = append(issues, referenceValidationIssue("tokens", issue))
+	}
+	issues = append(issues, validateTokenTypes("tokens", m.TokenTypes, resolved, m.Tokens)...)
+
+	for name, variant := range m.Variants {
+		label := fmt.Sprintf("variants.%s.tokens", name)
+		resolved, refIssues := resolveTokenReferences(m.variantTokens(name))
+		for _, issue := range refIssues {
+			if _, declared := variant.Tokens[issue.Token]; declared {
+				issues = append(issues, referenceValidationIssue(label, issue))
+			}
+		}
+		issues = append(issues, validateTokenTypes(label, m.TokenTypes, resolved, variant.Tokens)...)
+	}
+
+	sortValidationIssues(issues)
+	return issues
 }
 
-// TokensForVariant merges base tokens with the requested variant (variant tokens take precedence).
-func (m Manifest) TokensForVariant(variant string) map[string]string {
-	if variant == "" {
-		return cloneStringMap(m.Tokens)
+// axisIssues checks that axes are named once and only list defined variants, each in a single axis.
+// Values are only checked against the variants when the manifest does not inherit any.
+func (m *Manifest) axisIssues(inherits bool) []ValidationIssue {
+	var issues []ValidationIssue
+	report := func(path, code, message string) {
+		issues = append(issues, ValidationIssue{Path: path, Code: code, Severity: SeverityError, Message: message})
 	}
 
-	selected, ok := m.Variants[variant]
-	if !ok || len(selected.Tokens) == 0 {
-		return cloneStringMap(m.Tokens)
+	axisOf := map[string]string{}
+	seen := map[string]bool{}
+	for i, axis := range m.Axes {
+		path := fmt.Sprintf("axes.%d", i)
+		name := strings.TrimSpace(axis.Name)
+		switch {
+		case name == "":
+			report(path+".name", IssueRequired, fmt.Sprintf("axes entry %d name is required", i))
+		case seen[name]:
+			report(path+".name", IssueInvalidValue, fmt.Sprintf("axis '%s' is declared more than once", axis.Name))
+		}
+		seen[name] = true
+
+		if len(axis.Values) == 0 {
+			report(path+".values", IssueRequired, fmt.Sprintf("axis '%s' has no values", axis.Name))
+		}
+		for j, value := range axis.Values {
+			valuePath := fmt.Sprintf("%s.values.%d", path, j)
+			if _, ok := m.Variants[value]; !ok && !inherits {
+				report(valuePath, IssueUnknownVariant, fmt.Sprintf("axis '%s' value '%s' is not a defined variant", axis.Name, value))
+				continue
+			}
+			if other, ok := axisOf[value]; ok {
+				report(valuePath, IssueInvalidValue, fmt.Sprintf("variant '%s' already belongs to axis '%s'", value, other))
+				continue
+			}
+			axisOf[value] = axis.Name
+		}
+		if axis.Default != "" && !containsString(axis.Values, axis.Default) {
+			report(path+".default", IssueUnknownVariant, fmt.Sprintf("axis '%s' default '%s' is not one of its values", axis.Name, axis.Default))
+		}
+	}
+	return issues
+}
+
+func (m *Manifest) definesToken(key string) bool {
+	if _, ok := m.Tokens[key]; ok {
+		return true
+	}
+	for _, variant := range m.Variants {
+		if _, ok := variant.Tokens[key]; ok {
+			return true
+		}
 	}
+	return false
+}
+
+func sortValidationIssues(issues []ValidationIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Path != issues[j].Path {
+			return issues[i].Path < issues[j].Path
+		}
+		if issues[i].Code != issues[j].Code {
+			return issues[i].Code < issues[j].Code
+		}
+		return issues[i].Message < issues[j].Message
+	})
+}
+
+// VariantChain returns the variant followed by its fallback variants, nearest first.
+// Names the manifest does not define end the chain, as does a fallback cycle; an empty
+// or unknown variant yields an empty chain (base values only).
+func (m Manifest) VariantChain(variant string) []string {
+	var chain []string
+	seen := map[string]bool{}
+	for name := strings.TrimSpace(variant); name != "" && !seen[name]; {
+		selected, ok := m.Variants[name]
+		if !ok {
+			break
+		}
+		seen[name] = true
+		chain = append(chain, name)
+		name = strings.TrimSpace(selected.Fallback)
+	}
+	return chain
+}
 
+// variantFallbackCycle reports whether following fallbacks from variant leads back to a visited variant.
+func (m Manifest) variantFallbackCycle(variant string) bool {
+	chain := m.VariantChain(variant)
+	if len(chain) == 0 {
+		return false
+	}
+	last := strings.TrimSpace(m.Variants[chain[len(chain)-1]].Fallback)
+	for _, name := range chain {
+		if name == last {
+			return true
+		}
+	}
+	return false
+}
+
+// variantLayers expands variants (lowest precedence first) and their fallback chains into the
+// defined variants that apply, highest precedence first. A variant listed twice keeps its higher rank.
+func (m Manifest) variantLayers(variants []string) []string {
+	var merged []string
+	for _, variant := range variants {
+		chain := m.VariantChain(variant)
+		for i := len(chain) - 1; i >= 0; i-- {
+			merged = append(removeString(merged, chain[i]), chain[i])
+		}
+	}
+
+	layers := make([]string, len(merged))
+	for i, name := range merged {
+		layers[len(merged)-1-i] = name
+	}
+	return layers
+}
+
+// variantTokens merges base tokens with the variant layers, lowest precedence first, without resolving references.
+func (m Manifest) variantTokens(variants ...string) map[string]string {
 	merged := cloneStringMap(m.Tokens)
-	for k, v := range selected.Tokens {
-		merged[k] = v
+	layers := m.variantLayers(variants)
+	for i := len(layers) - 1; i >= 0; i-- {
+		for k, v := range m.Variants[layers[i]].Tokens {
+			merged[k] = v
+		}
 	}
 	return merged
 }
 
+// TokensForVariant merges base tokens with the requested variant and its fallback chain (nearer
+// variants take precedence) and resolves "{token}" references against the merged set.
+func (m Manifest) TokensForVariant(variant string) map[string]string {
+	return m.TokensForVariants(variant)
+}
+
+// TokensForVariants merges base tokens with several variants, later variants taking precedence
+// (see AxisVariants for axis order), and resolves "{token}" references against the merged set.
+func (m Manifest) TokensForVariants(variants ...string) map[string]string {
+	resolved, _ := resolveTokenReferences(m.variantTokens(variants...))
+	return resolved
+}
+
 // CSSVariables returns a CSS variable map (prefixed with "--" unless overridden) for a variant.
 func (m Manifest) CSSVariables(prefix, variant string) map[string]string {
 	if prefix == "" {
@@ -121,6 +402,33 @@ func (m Manifest) CSSVariables(prefix, variant string) map[string]string {
 	return vars
 }
 
+func referenceValidationIssue(label string, issue tokenReferenceIssue) ValidationIssue {
+	if issue.Cycle {
+		return ValidationIssue{
+			Path:     label + "." + issue.Token,
+			Code:     IssueReferenceCycle,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("%s entry '%s' has a reference cycle through '%s'", label, issue.Token, issue.Reference),
+		}
+	}
+	return ValidationIssue{
+		Path:     label + "." + issue.Token,
+		Code:     IssueUnknownReference,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("%s entry '%s' references unknown token '%s'", label, issue.Token, issue.Reference),
+	}
+}
+
+func removeString(values []string, drop string) []string {
+	out := values[:0]
+	for _, value := range values {
+		if value != drop {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
 func cloneStringMap(src map[string]string) map[string]string {
 	if len(src) == 0 {
 		return map[string]string{}
@@ -1,6 +1,9 @@
 package theme
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestManifestValidate(t *testing.T) {
 	t.Run("requires name and version", func(t *testing.T) {
@@ -84,3 +87,100 @@ func TestCSSVariables(t *testing.T) {
 		t.Fatalf("expected CSS variable to be prefixed, got %v", vars)
 	}
 }
+
+func TestValidationIssuesAreStructuredAndSorted(t *testing.T) {
+	m := Manifest{
+		Version: "1.0.0",
+		Tokens: map[string]string{
+			"primary":   "",
+			"button.bg": "{color.primary}",
+		},
+		Variants: map[string]Variant{
+			"dark": {Tokens: map[string]string{"primary": ""}},
+		},
+	}
+
+	err := m.Validate()
+	verr, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	expected := []ValidationIssue{
+		{Path: "name", Code: IssueRequired, Severity: SeverityError, Message: "name is required"},
+		{Path: "tokens.button.bg", Code: IssueUnknownReference, Severity: SeverityError, Message: "tokens entry 'button.bg' references unknown token 'color.primary'"},
+		{Path: "tokens.primary", Code: IssueEmptyValue, Severity: SeverityError, Message: "tokens entry 'primary' is empty"},
+		{Path: "variants.dark.tokens.primary", Code: IssueEmptyValue, Severity: SeverityError, Message: "variants.dark.tokens entry 'primary' is empty"},
+	}
+	if len(verr.Issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %+v", len(expected), verr.Issues)
+	}
+	for i, issue := range expected {
+		if verr.Issues[i] != issue {
+			t.Fatalf("issue %d: expected %+v, got %+v", i, issue, verr.Issues[i])
+		}
+	}
+
+	want := "manifest validation failed: name is required; tokens entry 'button.bg' references unknown token 'color.primary'; tokens entry 'primary' is empty; variants.dark.tokens entry 'primary' is empty"
+	if verr.Error() != want {
+		t.Fatalf("unexpected error output: %s", verr.Error())
+	}
+}
+
+func TestValidationErrorMessageSkipsWarnings(t *testing.T) {
+	m := Manifest{
+		Name:       "default",
+		Version:    "1.0.0",
+		Tokens:     map[string]string{"primary": ""},
+		TokenTypes: map[string]string{"secondary": "color"},
+	}
+
+	verr, ok := m.Validate().(ValidationError)
+	if !ok || len(verr.Issues) != 2 {
+		t.Fatalf("expected an error and a warning, got %v", m.Validate())
+	}
+	if want := "manifest validation failed: tokens entry 'primary' is empty"; verr.Error() != want {
+		t.Fatalf("unexpected error output: %s", verr.Error())
+	}
+}
+
+func TestValidateIgnoresWarnings(t *testing.T) {
+	m := Manifest{
+		Name:       "default",
+		Version:    "1.0.0",
+		Tokens:     map[string]string{"primary": "#fff"},
+		TokenTypes: map[string]string{"secondary": "color"},
+	}
+
+	if err := m.Validate(); err != nil {
+		t.Fatalf("expected warnings not to fail validation, got %v", err)
+	}
+	issues := m.ValidationIssues()
+	if len(issues) != 1 || issues[0].Severity != SeverityWarning || issues[0].Code != IssueUnusedType {
+		t.Fatalf("expected unused type warning, got %+v", issues)
+	}
+}
+
+func TestValidateVariantFallbacks(t *testing.T) {
+	m := Manifest{
+		Name:    "default",
+		Version: "1.0.0",
+		Variants: map[string]Variant{
+			"a":     {Fallback: "b"},
+			"b":     {Fallback: "a"},
+			"dim":   {Fallback: "sepia"},
+			"hc":    {Fallback: "dark", Tokens: map[string]string{"border": "{fg}"}},
+			"dark":  {Tokens: map[string]string{"fg": "white"}},
+			"plain": {},
+		},
+	}
+
+	var got []string
+	for _, issue := range m.ValidationIssues() {
+		got = append(got, issue.Path+":"+issue.Code)
+	}
+	want := "variants.a.fallback:reference_cycle,variants.b.fallback:reference_cycle,variants.dim.fallback:unknown_variant"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected issues: %v", got)
+	}
+}
@@ -0,0 +1,187 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// MiddlewareOption configures Middleware.
+type MiddlewareOption func(*middlewareOptions)
+
+type middlewareOptions struct {
+	themeCookie   string
+	variantCookie string
+	cookie        http.Cookie
+	vary          bool
+	acceptCH      bool
+	partials      map[string]string
+	onError       func(*http.Request, error)
+	query         []QueryOption
+}
+
+// WithPersistCookies stores a theme or variant chosen through the query string in the named cookies
+// (an empty name skips that value) so later requests keep it; attrs supplies Path, MaxAge, Secure,
+// HttpOnly, SameSite and Domain. Pair it with a CookieSource reading the same names.
+func WithPersistCookies(themeCookie, variantCookie string, attrs http.Cookie) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.themeCookie = themeCookie
+		o.variantCookie = variantCookie
+		o.cookie = attrs
+	}
+}
+
+// WithVaryHeaders adds the request headers read by the negotiator's sources to the Vary response header.
+func WithVaryHeaders() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.vary = true
+	}
+}
+
+// WithAcceptClientHints asks browsers to send the Sec-CH-Prefers-Color-Scheme hint via Accept-CH.
+func WithAcceptClientHints() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.acceptCH = true
+	}
+}
+
+// WithRendererPartials sets the fallback partials used by RendererConfigFromContext.
+func WithRendererPartials(fallbacks map[string]string) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.partials = cloneStringMap(fallbacks)
+	}
+}
+
+// WithNegotiationErrorHandler is called when no theme can be negotiated, e.g. to log the error.
+func WithNegotiationErrorHandler(fn func(*http.Request, error)) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.onError = fn
+	}
+}
+
+// WithQueryOptions passes lookup options (e.g. WithConstraint or WithFallback) to every Negotiate call.
+func WithQueryOptions(opts ...QueryOption) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.query = append(o.query, opts...)
+	}
+}
+
+type contextKey struct{}
+
+type contextValue struct {
+	negotiation *Negotiation
+	partials    map[string]string
+}
+
+// Middleware negotiates a theme for every request and stores the result in the request context
+// (see SelectionFromContext). Candidates that fail to resolve degrade to the selector's DefaultTheme;
+// when even that fails (or negotiator is nil) the request proceeds without a selection instead of failing.
+func Middleware(negotiator *Negotiator, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	settings := middlewareOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&settings)
+		}
+	}
+	var vary []string
+	if negotiator != nil {
+		vary = negotiator.varyHeaders()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if settings.vary && len(vary) > 0 {
+				w.Header().Add("Vary", strings.Join(vary, ", "))
+			}
+			if settings.acceptCH {
+				w.Header().Add("Accept-CH", ClientHintColorScheme)
+			}
+
+			result, err := negotiate(negotiator, r, settings.query)
+			if err != nil {
+				if settings.onError != nil {
+					settings.onError(r, err)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			settings.persist(w, result)
+			next.ServeHTTP(w, r.WithContext(ContextWithNegotiation(r.Context(), result, settings.partials)))
+		})
+	}
+}
+
+func negotiate(negotiator *Negotiator, r *http.Request, opts []QueryOption) (*Negotiation, error) {
+	if negotiator == nil {
+		return nil, errors.New("theme negotiator is nil")
+	}
+	return negotiator.Negotiate(r, opts...)
+}
+
+// persist writes the persistence cookies for values chosen through the query string.
+func (o middlewareOptions) persist(w http.ResponseWriter, result *Negotiation) {
+	write := func(name, value string) {
+		cookie := o.cookie
+		cookie.Name = name
+		cookie.Value = value
+		http.SetCookie(w, &cookie)
+	}
+	if o.themeCookie != "" && result.ThemeSource == SourceQuery {
+		write(o.themeCookie, result.Selection.Theme)
+	}
+	if o.variantCookie != "" && result.VariantSource == SourceQuery && result.Selection.Variant != "" {
+		write(o.variantCookie, result.Selection.Variant)
+	}
+}
+
+// varyHeaders returns the de-duplicated request headers read by the sources.
+func (n *Negotiator) varyHeaders() []string {
+	var headers []string
+	seen := map[string]bool{}
+	for _, source := range n.Sources {
+		for _, header := range source.Vary {
+			key := http.CanonicalHeaderKey(header)
+			if !seen[key] {
+				seen[key] = true
+				headers = append(headers, key)
+			}
+		}
+	}
+	return headers
+}
+
+// ContextWithNegotiation returns a copy of ctx carrying the negotiation result and the fallback partials
+// used by RendererConfigFromContext, as Middleware does.
+func ContextWithNegotiation(ctx context.Context, result *Negotiation, partials map[string]string) context.Context {
+	return context.WithValue(ctx, contextKey{}, contextValue{negotiation: result, partials: partials})
+}
+
+// NegotiationFromContext returns the negotiation result stored by Middleware.
+func NegotiationFromContext(ctx context.Context) (*Negotiation, bool) {
+	value, ok := ctx.Value(contextKey{}).(contextValue)
+	if !ok || value.negotiation == nil {
+		return nil, false
+	}
+	return value.negotiation, true
+}
+
+// SelectionFromContext returns the Selection stored by Middleware.
+func SelectionFromContext(ctx context.Context) (*Selection, bool) {
+	result, ok := NegotiationFromContext(ctx)
+	if !ok || result.Selection == nil {
+		return nil, false
+	}
+	return result.Selection, true
+}
+
+// RendererConfigFromContext builds the RendererConfig for the stored Selection using the partials
+// configured with WithRendererPartials.
+func RendererConfigFromContext(ctx context.Context) (RendererConfig, bool) {
+	value, ok := ctx.Value(contextKey{}).(contextValue)
+	if !ok || value.negotiation == nil || value.negotiation.Selection == nil {
+		return RendererConfig{}, false
+	}
+	return value.negotiation.Selection.RendererTheme(value.partials), true
+}
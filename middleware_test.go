@@ -0,0 +1,132 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddlewareStoresSelection(t *testing.T) {
+	reg := negotiationRegistry(t)
+	if err := reg.Register(&Manifest{
+		Name:      "partials",
+		Version:   "1.0.0",
+		Templates: map[string]string{"layout.header": "partials/header.tmpl"},
+	}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	negotiator := NewNegotiator(Selector{Registry: reg, DefaultTheme: "default", DefaultVariant: "light"})
+
+	var got *Selection
+	var config RendererConfig
+	handler := Middleware(negotiator,
+		WithPersistCookies("theme", "theme_variant", http.Cookie{Path: "/", HttpOnly: true}),
+		WithVaryHeaders(),
+		WithAcceptClientHints(),
+		WithRendererPartials(map[string]string{"layout.header": "fallback/header.tmpl"}),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = SelectionFromContext(r.Context())
+		config, _ = RendererConfigFromContext(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?theme=partials", nil))
+
+	if got == nil || got.Theme != "partials" {
+		t.Fatalf("expected selection in context, got %+v", got)
+	}
+	if config.Partials["layout.header"] != "partials/header.tmpl" {
+		t.Fatalf("expected renderer config partials, got %+v", config.Partials)
+	}
+	if vary := rec.Header().Get("Vary"); vary != "Cookie, X-Theme, X-Theme-Variant, Sec-Ch-Prefers-Color-Scheme" {
+		t.Fatalf("unexpected Vary header: %q", vary)
+	}
+	if rec.Header().Get("Accept-CH") != ClientHintColorScheme {
+		t.Fatalf("expected Accept-CH header, got %q", rec.Header().Get("Accept-CH"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "theme" || cookies[0].Value != "partials" || !cookies[0].HttpOnly {
+		t.Fatalf("expected theme cookie only, got %+v", cookies)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "acme"})
+	handler.ServeHTTP(rec, req)
+	if got.Theme != "acme" || len(rec.Result().Cookies()) != 0 {
+		t.Fatalf("expected cookie theme without rewriting the cookie, got %s, %+v", got.Theme, rec.Result().Cookies())
+	}
+}
+
+func TestMiddlewareDegradesOnLookupErrors(t *testing.T) {
+	reg := negotiationRegistry(t)
+	negotiator := NewNegotiator(Selector{Registry: reg, DefaultTheme: "default"})
+
+	var got *Selection
+	handler := Middleware(negotiator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = SelectionFromContext(r.Context())
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?theme=missing", nil))
+	if rec.Code != http.StatusOK || got == nil || got.Theme != "default" {
+		t.Fatalf("expected default theme, got %d %+v", rec.Code, got)
+	}
+
+	var reported error
+	var ok bool
+	broken := NewNegotiator(Selector{Registry: NewRegistry(), DefaultTheme: "default"})
+	handler = Middleware(broken, WithNegotiationErrorHandler(func(r *http.Request, err error) {
+		reported = err
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok = SelectionFromContext(r.Context())
+	}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || ok {
+		t.Fatalf("expected request to proceed without a selection, got %d, %v", rec.Code, ok)
+	}
+	if !errors.Is(reported, ErrThemeNotFound) {
+		t.Fatalf("expected ErrThemeNotFound to be reported, got %v", reported)
+	}
+
+	reported = nil
+	handler = Middleware(nil, WithVaryHeaders(), WithNegotiationErrorHandler(func(r *http.Request, err error) {
+		reported = err
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || reported == nil {
+		t.Fatalf("expected a nil negotiator to be reported, got %d, %v", rec.Code, reported)
+	}
+}
+
+func TestMiddlewareQueryOptions(t *testing.T) {
+	reg := negotiationRegistry(t)
+	if err := reg.Register(&Manifest{Name: "default", Version: "2.0.0"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	negotiator := NewNegotiator(Selector{Registry: reg, DefaultTheme: "default"})
+
+	var got *Selection
+	handler := Middleware(negotiator, WithQueryOptions(WithConstraint("^1")))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = SelectionFromContext(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if got == nil || got.Version != "1.0.0" {
+		t.Fatalf("expected query options to pin default@^1, got %+v", got)
+	}
+}
+
+func TestContextWithNegotiationPartials(t *testing.T) {
+	sel, err := Selector{Registry: negotiationRegistry(t)}.Select("acme", "dark")
+	if err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	ctx := ContextWithNegotiation(context.Background(), &Negotiation{Selection: sel}, map[string]string{"layout.header": "fallback/header.tmpl"})
+	config, ok := RendererConfigFromContext(ctx)
+	if !ok || config.Theme != "acme" || config.Partials["layout.header"] != "fallback/header.tmpl" {
+		t.Fatalf("expected renderer config with fallback partials, got %+v (%v)", config, ok)
+	}
+}
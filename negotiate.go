@@ -0,0 +1,297 @@
+package theme
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Request source names reported by Negotiation.
+const (
+	SourceQuery      = "query"
+	SourceCookie     = "cookie"
+	SourceHeader     = "header"
+	SourceClientHint = "client-hint"
+	SourcePath       = "path"
+	SourceHost       = "host"
+	SourceDefault    = "default"
+)
+
+// ClientHintColorScheme is the user preference client hint consulted by ClientHintSource.
+const ClientHintColorScheme = "Sec-CH-Prefers-Color-Scheme"
+
+// RequestSource extracts theme and variant candidates from a request; an empty value means the
+// source has no opinion. Axes optionally supplies variant axis values (axis name → value). Vary lists
+// the request headers the source reads, for HTTP caching.
+type RequestSource struct {
+	Name    string
+	Vary    []string
+	Resolve func(r *http.Request) (theme, variant string)
+	Axes    func(r *http.Request) map[string]string
+}
+
+// Negotiator resolves a Selection from an HTTP request by consulting Sources in order.
+type Negotiator struct {
+	Selector ThemeSelector
+	Sources  []RequestSource
+}
+
+// Negotiation is the outcome of Negotiate: the Selection plus the sources that supplied its theme and
+// variant (SourceDefault when the selector's defaults were used). AxisSources maps each requested axis
+// to the source that supplied its value.
+type Negotiation struct {
+	Selection     *Selection
+	ThemeSource   string
+	VariantSource string
+	AxisSources   map[string]string
+}
+
+// NewNegotiator builds a Negotiator over selector, using DefaultRequestSources when none are given.
+func NewNegotiator(selector ThemeSelector, sources ...RequestSource) *Negotiator {
+	if len(sources) == 0 {
+		sources = DefaultRequestSources()
+	}
+	return &Negotiator{Selector: selector, Sources: sources}
+}
+
+// DefaultRequestSources returns the query ("theme", "variant"), cookie ("theme", "theme_variant"),
+// header ("X-Theme", "X-Theme-Variant") and color scheme client hint sources, in that order.
+func DefaultRequestSources() []RequestSource {
+	return []RequestSource{
+		QuerySource("theme", "variant"),
+		CookieSource("theme", "theme_variant"),
+		HeaderSource("X-Theme", "X-Theme-Variant"),
+		ClientHintSource(),
+	}
+}
+
+type negotiationCandidate struct {
+	value  string
+	source string
+}
+
+// Negotiate picks the first theme candidate the selector resolves without falling back to its default
+// theme, and the first variant candidate that theme defines. Candidates that do not resolve fall through
+// to the next source; when none do, the selector's DefaultTheme and DefaultVariant apply, and their error
+// is returned if they fail too. Each theme candidate is resolved once and the variant candidates are
+// checked against its manifest. When the selector implements AxisSelector and the theme declares axes,
+// the axis values from Sources (first source wins per axis) are resolved through SelectAxes onto the
+// negotiated selection.
+func (n Negotiator) Negotiate(r *http.Request, opts ...QueryOption) (*Negotiation, error) {
+	if n.Selector == nil {
+		return nil, errors.New("theme selector is nil")
+	}
+
+	themes, variants := n.candidates(r)
+	themes = append(themes, negotiationCandidate{source: SourceDefault})
+
+	var lastErr error
+	for _, theme := range themes {
+		sel, err := n.Selector.Select(theme.value, "", opts...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if theme.value != "" && hasSelectionFallback(sel, FallbackReasonThemeNotFound) {
+			continue
+		}
+
+		result := &Negotiation{Selection: sel, ThemeSource: theme.source, VariantSource: SourceDefault}
+		for _, variant := range variants {
+			if !hasVariant(sel.Manifest, variant.value) {
+				continue
+			}
+			if sel, err = n.Selector.Select(theme.value, variant.value, opts...); err != nil {
+				return nil, err
+			}
+			result.Selection, result.VariantSource = sel, variant.source
+			break
+		}
+		if err := n.negotiateAxes(r, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	return nil, lastErr
+}
+
+// negotiateAxes resolves the requested axis values against the negotiated theme version.
+func (n Negotiator) negotiateAxes(r *http.Request, result *Negotiation) error {
+	selector, ok := n.Selector.(AxisSelector)
+	sel := result.Selection
+	if !ok || sel.Manifest == nil || len(sel.Manifest.Axes) == 0 {
+		return nil
+	}
+
+	axes := map[string]string{}
+	sources := map[string]string{}
+	for _, source := range n.Sources {
+		if source.Axes == nil {
+			continue
+		}
+		for name, value := range source.Axes(r) {
+			name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+			if name == "" || value == "" || axes[name] != "" {
+				continue
+			}
+			axes[name], sources[name] = value, source.Name
+		}
+	}
+
+	axisSel, err := selector.SelectAxes(sel.Theme, axes, WithVersion(sel.Version), WithoutFallback())
+	if err != nil {
+		return err
+	}
+	sel.Axes, sel.RequestedAxes = axisSel.Axes, axisSel.RequestedAxes
+	sel.Fallbacks = append(sel.Fallbacks, axisSel.Fallbacks...)
+	result.AxisSources = sources
+	return nil
+}
+
+// candidates collects the non-empty, de-duplicated theme and variant values in source order.
+func (n Negotiator) candidates(r *http.Request) (themes, variants []negotiationCandidate) {
+	seenThemes := map[string]bool{}
+	seenVariants := map[string]bool{}
+	for _, source := range n.Sources {
+		if source.Resolve == nil {
+			continue
+		}
+		theme, variant := source.Resolve(r)
+		if theme = strings.TrimSpace(theme); theme != "" && !seenThemes[theme] {
+			seenThemes[theme] = true
+			themes = append(themes, negotiationCandidate{value: theme, source: source.Name})
+		}
+		if variant = strings.TrimSpace(variant); variant != "" && !seenVariants[variant] {
+			seenVariants[variant] = true
+			variants = append(variants, negotiationCandidate{value: variant, source: source.Name})
+		}
+	}
+	return themes, variants
+}
+
+func hasSelectionFallback(sel *Selection, code string) bool {
+	for _, fallback := range sel.Fallbacks {
+		if fallback.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// QuerySource reads the theme and variant from URL query parameters; an empty name skips that value.
+func QuerySource(themeParam, variantParam string) RequestSource {
+	return RequestSource{
+		Name: SourceQuery,
+		Resolve: func(r *http.Request) (string, string) {
+			query := r.URL.Query()
+			return optionalValue(themeParam, query.Get), optionalValue(variantParam, query.Get)
+		},
+	}
+}
+
+// CookieSource reads the theme and variant from cookies; an empty name skips that value.
+func CookieSource(themeCookie, variantCookie string) RequestSource {
+	return RequestSource{
+		Name: SourceCookie,
+		Vary: []string{"Cookie"},
+		Resolve: func(r *http.Request) (string, string) {
+			read := func(name string) string {
+				cookie, err := r.Cookie(name)
+				if err != nil {
+					return ""
+				}
+				return cookie.Value
+			}
+			return optionalValue(themeCookie, read), optionalValue(variantCookie, read)
+		},
+	}
+}
+
+// HeaderSource reads the theme and variant from request headers; an empty name skips that value.
+func HeaderSource(themeHeader, variantHeader string) RequestSource {
+	return RequestSource{
+		Name: SourceHeader,
+		Vary: nonEmpty(themeHeader, variantHeader),
+		Resolve: func(r *http.Request) (string, string) {
+			return optionalValue(themeHeader, r.Header.Get), optionalValue(variantHeader, r.Header.Get)
+		},
+	}
+}
+
+// ClientHintSource reads the variant from the Sec-CH-Prefers-Color-Scheme client hint ("light" or "dark").
+// Browsers only send it after the server lists it in Accept-CH.
+func ClientHintSource() RequestSource {
+	return RequestSource{
+		Name: SourceClientHint,
+		Vary: []string{ClientHintColorScheme},
+		Resolve: func(r *http.Request) (string, string) {
+			return "", strings.ToLower(strings.Trim(strings.TrimSpace(r.Header.Get(ClientHintColorScheme)), `"`))
+		},
+	}
+}
+
+// PathPrefixSource reads the theme from the path segment following prefix, e.g. "/themes/" matches
+// "/themes/acme/dashboard" as theme "acme".
+func PathPrefixSource(prefix string) RequestSource {
+	return RequestSource{
+		Name: SourcePath,
+		Resolve: func(r *http.Request) (string, string) {
+			rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+			if !ok {
+				return "", ""
+			}
+			theme, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+			return theme, ""
+		},
+	}
+}
+
+// HostSource maps the request host (port stripped, case-insensitive) to a theme. Keys may start with
+// "*." to match any subdomain; exact matches win over wildcards and longer wildcards over shorter ones.
+func HostSource(themes map[string]string) RequestSource {
+	normalized := make(map[string]string, len(themes))
+	for host, theme := range themes {
+		normalized[strings.ToLower(strings.TrimSpace(host))] = theme
+	}
+
+	return RequestSource{
+		Name: SourceHost,
+		Resolve: func(r *http.Request) (string, string) {
+			host := strings.ToLower(r.Host)
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			if theme, ok := normalized[host]; ok {
+				return theme, ""
+			}
+			for rest := host; ; {
+				_, parent, ok := strings.Cut(rest, ".")
+				if !ok {
+					return "", ""
+				}
+				if theme, ok := normalized["*."+parent]; ok {
+					return theme, ""
+				}
+				rest = parent
+			}
+		},
+	}
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, value := range values {
+		if value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func optionalValue(name string, get func(string) string) string {
+	if name == "" {
+		return ""
+	}
+	return get(name)
+}
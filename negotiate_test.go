@@ -0,0 +1,188 @@
+package theme
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func negotiationRegistry(t *testing.T) *MemoryRegistry {
+	t.Helper()
+	reg := NewRegistry()
+	for _, manifest := range []*Manifest{
+		{Name: "default", Version: "1.0.0", Variants: map[string]Variant{"light": {}, "dark": {}}},
+		{Name: "acme", Version: "1.0.0", Variants: map[string]Variant{"light": {}, "dark": {}, "contrast": {}}},
+		{Name: "globex", Version: "1.0.0", Variants: map[string]Variant{"light": {}}},
+	} {
+		if err := reg.Register(manifest); err != nil {
+			t.Fatalf("register %s: %v", manifest.Name, err)
+		}
+	}
+	return reg
+}
+
+func TestNegotiatorSourceOrder(t *testing.T) {
+	selector := Selector{Registry: negotiationRegistry(t), DefaultTheme: "default", DefaultVariant: "light"}
+	negotiator := NewNegotiator(selector)
+
+	req := httptest.NewRequest(http.MethodGet, "/?theme=acme", nil)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "globex"})
+	req.AddCookie(&http.Cookie{Name: "theme_variant", Value: "contrast"})
+	req.Header.Set(ClientHintColorScheme, `"dark"`)
+
+	result, err := negotiator.Negotiate(req)
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if result.Selection.Theme != "acme" || result.ThemeSource != SourceQuery {
+		t.Fatalf("expected query theme to win, got %s from %s", result.Selection.Theme, result.ThemeSource)
+	}
+	if result.Selection.Variant != "contrast" || result.VariantSource != SourceCookie {
+		t.Fatalf("expected cookie variant to win, got %s from %s", result.Selection.Variant, result.VariantSource)
+	}
+}
+
+func TestNegotiatorFallsThroughUnresolvedCandidates(t *testing.T) {
+	selector := Selector{Registry: negotiationRegistry(t), DefaultTheme: "default", DefaultVariant: "light"}
+	negotiator := NewNegotiator(selector)
+
+	req := httptest.NewRequest(http.MethodGet, "/?theme=missing&variant=contrast", nil)
+	req.Header.Set("X-Theme", "globex")
+	req.Header.Set(ClientHintColorScheme, "dark")
+
+	result, err := negotiator.Negotiate(req)
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if result.Selection.Theme != "globex" || result.ThemeSource != SourceHeader {
+		t.Fatalf("expected header theme after unknown query theme, got %s from %s", result.Selection.Theme, result.ThemeSource)
+	}
+	if result.Selection.Variant != "light" || result.VariantSource != SourceDefault {
+		t.Fatalf("expected default variant when no candidate exists, got %s from %s", result.Selection.Variant, result.VariantSource)
+	}
+
+	result, err = negotiator.Negotiate(httptest.NewRequest(http.MethodGet, "/?theme=missing", nil))
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if result.Selection.Theme != "default" || result.ThemeSource != SourceDefault {
+		t.Fatalf("expected default theme, got %s from %s", result.Selection.Theme, result.ThemeSource)
+	}
+
+	strict := NewNegotiator(Selector{Registry: negotiationRegistry(t), StrictVariants: true}, QuerySource("theme", "variant"), ClientHintSource())
+	req = httptest.NewRequest(http.MethodGet, "/?theme=acme&variant=sepia", nil)
+	req.Header.Set(ClientHintColorScheme, "dark")
+	result, err = strict.Negotiate(req)
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if result.Selection.Variant != "dark" || result.VariantSource != SourceClientHint {
+		t.Fatalf("expected client hint variant after strict rejection, got %s from %s", result.Selection.Variant, result.VariantSource)
+	}
+}
+
+func TestNegotiatorPathAndHostSources(t *testing.T) {
+	selector := Selector{Registry: negotiationRegistry(t)}
+	negotiator := NewNegotiator(selector,
+		PathPrefixSource("/t/"),
+		HostSource(map[string]string{"globex.example.com": "globex", "*.example.com": "acme"}),
+	)
+
+	cases := []struct {
+		target, host, theme, source string
+	}{
+		{"http://globex.example.com/t/acme/dashboard", "", "acme", SourcePath},
+		{"/dashboard", "globex.example.com:8080", "globex", SourceHost},
+		{"/dashboard", "Tenant.EXAMPLE.com", "acme", SourceHost},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		if tc.host != "" {
+			req.Host = tc.host
+		}
+		result, err := negotiator.Negotiate(req)
+		if err != nil {
+			t.Fatalf("%s: negotiate: %v", tc.target, err)
+		}
+		if result.Selection.Theme != tc.theme || result.ThemeSource != tc.source {
+			t.Fatalf("%s: expected %s from %s, got %s from %s", tc.target, tc.theme, tc.source, result.Selection.Theme, result.ThemeSource)
+		}
+	}
+
+	if _, err := negotiator.Negotiate(httptest.NewRequest(http.MethodGet, "http://other.test/", nil)); err == nil {
+		t.Fatalf("expected error without candidates or default theme")
+	}
+}
+
+type plainSelector struct{ ThemeSelector }
+
+func TestNegotiatorResolvesAxes(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register(axesManifest()); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	selector := Selector{Registry: reg, DefaultTheme: "default"}
+	axisSource := func(name string, axes map[string]string) RequestSource {
+		return RequestSource{Name: name, Axes: func(*http.Request) map[string]string { return axes }}
+	}
+	sources := []RequestSource{
+		axisSource(SourceQuery, map[string]string{"scheme": "dark"}),
+		axisSource(SourceCookie, map[string]string{"scheme": "light", "density": "compact"}),
+	}
+
+	result, err := NewNegotiator(selector, sources...).Negotiate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	sel := result.Selection
+	if sel.Axes["scheme"] != "dark" || sel.Axes["density"] != "compact" || sel.Tokens()["space"] != "4px" {
+		t.Fatalf("expected axes from the first source per axis, got %v", sel.Axes)
+	}
+	if result.AxisSources["scheme"] != SourceQuery || result.AxisSources["density"] != SourceCookie || sel.RequestedAxes["scheme"] != "dark" {
+		t.Fatalf("unexpected axis sources %v, requested %v", result.AxisSources, sel.RequestedAxes)
+	}
+
+	result, err = NewNegotiator(plainSelector{selector}, sources...).Negotiate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil || result.Selection.Axes != nil {
+		t.Fatalf("expected selectors without SelectAxes to skip axes, got %+v (%v)", result, err)
+	}
+}
+
+type recordingSelector struct {
+	ThemeSelector
+	calls []string
+}
+
+func (s *recordingSelector) Select(themeName, variant string, opts ...QueryOption) (*Selection, error) {
+	s.calls = append(s.calls, themeName+"/"+variant)
+	return s.ThemeSelector.Select(themeName, variant, opts...)
+}
+
+func TestNegotiatorResolvesEachThemeOnce(t *testing.T) {
+	selector := &recordingSelector{ThemeSelector: Selector{Registry: negotiationRegistry(t), DefaultTheme: "default", DefaultVariant: "light"}}
+	req := httptest.NewRequest(http.MethodGet, "/?theme=missing&variant=contrast", nil)
+	req.Header.Set("X-Theme", "globex")
+	req.Header.Set("X-Theme-Variant", "light")
+	req.Header.Set(ClientHintColorScheme, "dark")
+
+	result, err := NewNegotiator(selector).Negotiate(req)
+	if err != nil {
+		t.Fatalf("negotiate: %v", err)
+	}
+	if result.Selection.Theme != "globex" || result.Selection.Variant != "light" || result.VariantSource != SourceHeader {
+		t.Fatalf("expected globex light from the header, got %+v", result)
+	}
+	if got := strings.Join(selector.calls, ","); got != "missing/,globex/,globex/light" {
+		t.Fatalf("expected one lookup per theme plus the variant selection, got %s", got)
+	}
+}
+
+func TestNegotiatorReturnsDefaultError(t *testing.T) {
+	negotiator := NewNegotiator(Selector{Registry: negotiationRegistry(t)}, QuerySource("theme", ""))
+	_, err := negotiator.Negotiate(httptest.NewRequest(http.MethodGet, "/?theme=missing", nil))
+	if err == nil || errors.Is(err, ErrThemeNotFound) || !strings.Contains(err.Error(), "name is required") {
+		t.Fatalf("expected the default theme's error, got %v", err)
+	}
+}
@@ -11,6 +11,8 @@ import (
 // Registry defines methods to register and retrieve theme manifests.
 type Registry interface {
 	Register(manifest *Manifest) error
+	Unregister(name, version string) error
+	UnregisterAll(name string) error
 	Get(name string, opts ...QueryOption) (*Manifest, error)
 	List() []ManifestRef
 }
@@ -21,23 +23,143 @@ type ThemeProvider interface {
 	Themes() []ManifestRef
 }
 
-// ManifestRef summarizes a stored manifest.
+// FilteredLister is implemented by registries that filter and paginate their own listing.
+type FilteredLister interface {
+	ListFiltered(filter ListFilter) ManifestPage
+}
+
+// FilteredThemeProvider is implemented by providers that filter and paginate their own listing.
+// Use FilterThemes to page any ThemeProvider.
+type FilteredThemeProvider interface {
+	ThemesFiltered(filter ListFilter) ManifestPage
+}
+
+// ManifestRef summarizes a stored manifest. Counts and variants describe the manifest as returned by
+// Get, i.e. with its extends chain flattened.
 type ManifestRef struct {
-	Name        string
-	Version     string
-	Description string
+	Name          string
+	Version       string
+	Description   string
+	Author        string
+	Tags          []string
+	Variants      []string
+	TokenCount    int
+	TemplateCount int
+	AssetCount    int
+	// Preview is the resolved path of the manifest's preview asset, empty when none is declared.
+	Preview string
+	// Latest is set on the version a plain Get(name) resolves to.
+	Latest bool
+	// Source labels the provider layer a ref came from when listed through a LayeredProvider.
+	Source string
 }
 
 // MemoryRegistry is a minimal in-memory implementation of Registry and ThemeProvider.
 type MemoryRegistry struct {
-	mu     sync.RWMutex
-	themes map[string]map[string]*Manifest
+	mu          sync.RWMutex
+	themes      map[string]map[string]*Manifest
+	subscribers map[int]func(RegistryEvent)
+	nextSubID   int
+	retain      int
+	stableOnly  bool
+}
+
+// RegistryOption configures a MemoryRegistry.
+type RegistryOption func(*MemoryRegistry)
+
+// WithRetention keeps at most n versions per theme; registering beyond that prunes the lowest versions
+// (which may be the one just registered when it is older than the retained ones). n <= 0 keeps everything.
+func WithRetention(n int) RegistryOption {
+	return func(r *MemoryRegistry) {
+		r.retain = n
+	}
+}
+
+// RegistryEventType classifies a registry change.
+type RegistryEventType string
+
+const (
+	// RegistryRegistered is emitted when a new name@version is stored.
+	RegistryRegistered RegistryEventType = "registered"
+	// RegistryReplaced is emitted when an existing name@version is overwritten.
+	RegistryReplaced RegistryEventType = "replaced"
+	// RegistryRemoved is emitted when a name@version is removed.
+	RegistryRemoved RegistryEventType = "removed"
+)
+
+// RegistryEvent describes a single registry change. PreviousVersion is the latest version stored
+// under Name before the change, empty when the theme was not registered.
+type RegistryEvent struct {
+	Type            RegistryEventType
+	Name            string
+	Version         string
+	PreviousVersion string
+}
+
+// WithStableLatest makes latest-version resolution skip pre-releases (e.g. "2.0.0-beta.1") while a
+// release exists; themes with only pre-releases still resolve to the highest one. Exact WithVersion
+// lookups are unaffected.
+func WithStableLatest() RegistryOption {
+	return func(r *MemoryRegistry) {
+		r.stableOnly = true
+	}
 }
 
 // NewRegistry constructs an empty MemoryRegistry.
-func NewRegistry() *MemoryRegistry {
-	return &MemoryRegistry{
-		themes: make(map[string]map[string]*Manifest),
+func NewRegistry(opts ...RegistryOption) *MemoryRegistry {
+	r := &MemoryRegistry{
+		themes:      make(map[string]map[string]*Manifest),
+		subscribers: make(map[int]func(RegistryEvent)),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Subscribe registers fn to receive every registry change and returns a function that cancels the
+// subscription. Events are delivered synchronously on the goroutine that made the change, after the
+// registry lock is released, so fn may call back into the registry.
+func (r *MemoryRegistry) Subscribe(fn func(RegistryEvent)) (unsubscribe func()) {
+	if fn == nil {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	id := r.nextSubID
+	r.nextSubID++
+	r.subscribers[id] = fn
+	r.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.subscribers, id)
+			r.mu.Unlock()
+		})
+	}
+}
+
+// subscriberList snapshots the current subscribers in subscription order. Callers must hold r.mu.
+func (r *MemoryRegistry) subscriberList() []func(RegistryEvent) {
+	ids := make([]int, 0, len(r.subscribers))
+	for id := range r.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	out := make([]func(RegistryEvent), len(ids))
+	for i, id := range ids {
+		out[i] = r.subscribers[id]
+	}
+	return out
+}
+
+func notify(subscribers []func(RegistryEvent), events ...RegistryEvent) {
+	for _, event := range events {
+		for _, fn := range subscribers {
+			fn(event)
+		}
 	}
 }
 
@@ -52,8 +174,30 @@ var (
 type QueryOption func(*queryOptions)
 
 type queryOptions struct {
-	version       string
-	allowFallback bool
+	version    string
+	constraint string
+	fallback   FallbackPolicy
+	raw        bool
+}
+
+// FallbackPolicy decides what a lookup returns when the requested version or constraint has no match.
+type FallbackPolicy string
+
+const (
+	// FallbackLatest returns the latest version overall (the default).
+	FallbackLatest FallbackPolicy = "latest"
+	// FallbackLatestMajor returns the latest version sharing the requested major version.
+	FallbackLatestMajor FallbackPolicy = "latest-major"
+	// FallbackError returns ErrVersionNotFound.
+	FallbackError FallbackPolicy = "error"
+)
+
+func newQueryOptions(opts []QueryOption) queryOptions {
+	settings := queryOptions{fallback: FallbackLatest}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	return settings
 }
 
 // WithVersion requests a specific manifest version.
@@ -63,14 +207,38 @@ func WithVersion(version string) QueryOption {
 	}
 }
 
+// WithConstraint requests the highest version matching a constraint such as "^1.2", "~1.4.0",
+// ">=1.0 <2.0" or "1.x". Pre-releases only match when the constraint names one of the same release.
+func WithConstraint(constraint string) QueryOption {
+	return func(opts *queryOptions) {
+		opts.constraint = strings.TrimSpace(constraint)
+	}
+}
+
+// WithFallback sets what happens when the requested version or constraint has no match.
+func WithFallback(policy FallbackPolicy) QueryOption {
+	return func(opts *queryOptions) {
+		opts.fallback = policy
+	}
+}
+
 // WithoutFallback disables fallback to the latest version when a specific one is missing.
+// It is shorthand for WithFallback(FallbackError).
 func WithoutFallback() QueryOption {
+	return WithFallback(FallbackError)
+}
+
+// WithoutExtends returns the manifest as stored, leaving its Extends chain unresolved. Wrappers such as
+// LayeredProvider use it to resolve parents across providers.
+func WithoutExtends() QueryOption {
 	return func(opts *queryOptions) {
-		opts.allowFallback = false
+		opts.raw = true
 	}
 }
 
 // Register validates and stores a manifest. Existing entries for the same name+version are overwritten.
+// Parents referenced via Extends are resolved on lookup, so registration order does not matter.
+// Subscribers receive a RegistryRegistered or RegistryReplaced event.
 func (r *MemoryRegistry) Register(manifest *Manifest) error {
 	if manifest == nil {
 		return fmt.Errorf("manifest is nil")
@@ -80,49 +248,211 @@ func (r *MemoryRegistry) Register(manifest *Manifest) error {
 	}
 
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	if _, ok := r.themes[manifest.Name]; !ok {
 		r.themes[manifest.Name] = make(map[string]*Manifest)
 	}
 
+	event := RegistryEvent{
+		Type:            RegistryRegistered,
+		Name:            manifest.Name,
+		Version:         manifest.Version,
+		PreviousVersion: latestVersion(r.themes[manifest.Name]),
+	}
+	if _, exists := r.themes[manifest.Name][manifest.Version]; exists {
+		event.Type = RegistryReplaced
+	}
+
 	r.themes[manifest.Name][manifest.Version] = copyManifest(manifest)
+	events := append([]RegistryEvent{event}, r.prune(manifest.Name)...)
+	subscribers := r.subscriberList()
+	r.mu.Unlock()
+
+	notify(subscribers, events...)
+	return nil
+}
+
+// Unregister removes a single version of a theme, returning ErrThemeNotFound or ErrVersionNotFound when
+// it is not stored. Subscribers receive a RegistryRemoved event.
+func (r *MemoryRegistry) Unregister(name, version string) error {
+	version = strings.TrimSpace(version)
+
+	r.mu.Lock()
+	versions, ok := r.themes[name]
+	if !ok || len(versions) == 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	}
+	if _, ok := versions[version]; !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, version)
+	}
+
+	event := r.remove(name, version)
+	subscribers := r.subscriberList()
+	r.mu.Unlock()
+
+	notify(subscribers, event)
 	return nil
 }
 
-// Get fetches a manifest by name, optionally constrained to a version with fallback to the latest.
+// UnregisterAll removes every version of a theme, returning ErrThemeNotFound when none is stored.
+// Subscribers receive one RegistryRemoved event per version, newest first.
+func (r *MemoryRegistry) UnregisterAll(name string) error {
+	r.mu.Lock()
+	versions, ok := r.themes[name]
+	if !ok || len(versions) == 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	}
+
+	ordered := sortedVersions(versions)
+	events := make([]RegistryEvent, 0, len(ordered))
+	for i := len(ordered) - 1; i >= 0; i-- {
+		events = append(events, r.remove(name, ordered[i]))
+	}
+	subscribers := r.subscriberList()
+	r.mu.Unlock()
+
+	notify(subscribers, events...)
+	return nil
+}
+
+// remove deletes name@version and returns the matching event. Callers must hold r.mu.
+func (r *MemoryRegistry) remove(name, version string) RegistryEvent {
+	event := RegistryEvent{
+		Type:            RegistryRemoved,
+		Name:            name,
+		Version:         version,
+		PreviousVersion: latestVersion(r.themes[name]),
+	}
+	delete(r.themes[name], version)
+	if len(r.themes[name]) == 0 {
+		delete(r.themes, name)
+	}
+	return event
+}
+
+// prune drops the lowest versions of name beyond the retention limit. Callers must hold r.mu.
+func (r *MemoryRegistry) prune(name string) []RegistryEvent {
+	versions := r.themes[name]
+	if r.retain <= 0 || len(versions) <= r.retain {
+		return nil
+	}
+
+	ordered := sortedVersions(versions)
+	var events []RegistryEvent
+	for _, version := range ordered[:len(ordered)-r.retain] {
+		events = append(events, r.remove(name, version))
+	}
+	return events
+}
+
+// Get fetches a manifest by name, optionally pinned with WithVersion or WithConstraint; misses follow
+// the WithFallback policy (latest overall by default).
+// Manifests that extend another theme are returned flattened with their parent chain unless
+// WithoutExtends is given.
 func (r *MemoryRegistry) Get(name string, opts ...QueryOption) (*Manifest, error) {
 	if strings.TrimSpace(name) == "" {
 		return nil, fmt.Errorf("name is required")
 	}
 
-	settings := queryOptions{allowFallback: true}
-	for _, opt := range opts {
-		opt(&settings)
-	}
+	settings := newQueryOptions(opts)
 
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	manifest, err := r.lookup(name, settings)
+	if err != nil {
+		return nil, err
+	}
+	if settings.raw {
+		return copyManifest(manifest), nil
+	}
+	return flattenManifest(manifest, func(parent, version string) (*Manifest, error) {
+		return r.lookup(parent, parentQuery(version))
+	})
+}
+
+// lookup returns the stored manifest for name/settings. Callers must hold r.mu.
+func (r *MemoryRegistry) lookup(name string, settings queryOptions) (*Manifest, error) {
 	versions, ok := r.themes[name]
 	if !ok || len(versions) == 0 {
 		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, name)
 	}
 
-	if settings.version != "" {
-		if manifest, ok := versions[settings.version]; ok {
-			return copyManifest(manifest), nil
+	stored := make([]string, 0, len(versions))
+	for version := range versions {
+		stored = append(stored, version)
+	}
+	version, err := selectVersion(name, stored, settings, r.stableOnly)
+	if err != nil {
+		return nil, err
+	}
+	return versions[version], nil
+}
+
+// selectVersion picks the version a query resolves to among the stored versions of a theme: an exact
+// WithVersion match, else the highest WithConstraint match, else the fallback policy; without either
+// option the latest version (skipping pre-releases when stableOnly and a release exists).
+func selectVersion(name string, versions []string, settings queryOptions, stableOnly bool) (string, error) {
+	if len(versions) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	}
+
+	latest := func(accept func(string) bool) string {
+		if stableOnly {
+			if stable := highestVersion(versions, func(v string) bool { return accept(v) && !isPrereleaseVersion(v) }); stable != "" {
+				return stable
+			}
+		}
+		return highestVersion(versions, accept)
+	}
+	all := func(string) bool { return true }
+
+	var requested string
+	var major uint64
+	var hasMajor bool
+
+	switch {
+	case settings.version != "":
+		for _, version := range versions {
+			if version == settings.version {
+				return version, nil
+			}
+		}
+		requested = settings.version
+		if parsed, err := parseSemVer(settings.version); err == nil {
+			major, hasMajor = parsed.major, true
 		}
-		if !settings.allowFallback {
-			return nil, fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, settings.version)
+	case settings.constraint != "":
+		constraint, err := parseConstraint(settings.constraint)
+		if err != nil {
+			return "", err
 		}
+		if version := highestVersion(versions, constraint.matches); version != "" {
+			return version, nil
+		}
+		requested = settings.constraint
+		major, hasMajor = constraint.major(), true
+	default:
+		return latest(all), nil
 	}
 
-	version := latestVersion(versions)
-	if version == "" {
-		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	switch settings.fallback {
+	case FallbackLatest:
+		return latest(all), nil
+	case FallbackLatestMajor:
+		if hasMajor {
+			inMajor := func(v string) bool {
+				parsed, err := parseSemVer(v)
+				return err == nil && parsed.major == major
+			}
+			if version := latest(inMajor); version != "" {
+				return version, nil
+			}
+		}
 	}
-	return copyManifest(versions[version]), nil
+	return "", fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, requested)
 }
 
 // List returns a sorted list of all stored manifests.
@@ -131,24 +461,36 @@ func (r *MemoryRegistry) List() []ManifestRef {
 	defer r.mu.RUnlock()
 
 	var refs []ManifestRef
-	for name, versions := range r.themes {
-		for version, manifest := range versions {
-			refs = append(refs, ManifestRef{
-				Name:        name,
-				Version:     version,
-				Description: manifest.Description,
+	for _, versions := range r.themes {
+		for _, manifest := range versions {
+			flattened, err := flattenManifest(manifest, func(parent, version string) (*Manifest, error) {
+				return r.lookup(parent, parentQuery(version))
 			})
+			if err != nil {
+				flattened = manifest
+			}
+			refs = append(refs, newManifestRef(flattened))
 		}
 	}
 
+	markLatest(refs, r.stableOnly)
+	sortManifestRefs(refs)
+	return refs
+}
+
+// ListFiltered returns the page of List matching filter.
+func (r *MemoryRegistry) ListFiltered(filter ListFilter) ManifestPage {
+	return filterManifestRefs(r.List(), filter)
+}
+
+// sortManifestRefs orders refs by name, newest version first.
+func sortManifestRefs(refs []ManifestRef) {
 	sort.Slice(refs, func(i, j int) bool {
 		if refs[i].Name == refs[j].Name {
 			return compareVersions(refs[i].Version, refs[j].Version) > 0
 		}
 		return refs[i].Name < refs[j].Name
 	})
-
-	return refs
 }
 
 // Theme is an alias for Get to satisfy ThemeProvider.
@@ -161,18 +503,75 @@ func (r *MemoryRegistry) Themes() []ManifestRef {
 	return r.List()
 }
 
+// ThemesFiltered is an alias for ListFiltered to satisfy FilteredThemeProvider.
+func (r *MemoryRegistry) ThemesFiltered(filter ListFilter) ManifestPage {
+	return r.ListFiltered(filter)
+}
+
+// latestVersion returns the highest stored version.
 func latestVersion(versions map[string]*Manifest) string {
-	var best string
+	stored := make([]string, 0, len(versions))
 	for version := range versions {
-		if best == "" || compareVersions(version, best) > 0 {
+		stored = append(stored, version)
+	}
+	return highestVersion(stored, func(string) bool { return true })
+}
+
+// highestVersion returns the highest accepted version; versions equal in precedence (differing only in
+// build metadata) are ordered lexically so the result is deterministic.
+func highestVersion(versions []string, accept func(version string) bool) string {
+	var best string
+	for _, version := range versions {
+		if !accept(version) {
+			continue
+		}
+		cmp := compareVersions(version, best)
+		if best == "" || cmp > 0 || (cmp == 0 && version > best) {
 			best = version
 		}
 	}
 	return best
 }
 
-// compareVersions performs a basic semantic version comparison (major.minor.patch).
+func isPrereleaseVersion(version string) bool {
+	parsed, err := parseSemVer(version)
+	return err == nil && parsed.isPrerelease()
+}
+
+// sortedVersions returns the stored versions in ascending order.
+func sortedVersions(versions map[string]*Manifest) []string {
+	out := make([]string, 0, len(versions))
+	for version := range versions {
+		out = append(out, version)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if cmp := compareVersions(out[i], out[j]); cmp != 0 {
+			return cmp < 0
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// compareVersions orders versions by SemVer 2.0 precedence (pre-releases below their release, build
+// metadata ignored). Versions that are not valid SemVer fall back to a dotted numeric comparison and
+// sort below valid ones.
 func compareVersions(a, b string) int {
+	av, aErr := parseSemVer(a)
+	bv, bErr := parseSemVer(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return av.compare(bv)
+	case aErr == nil:
+		return 1
+	case bErr == nil:
+		return -1
+	}
+	return compareVersionParts(a, b)
+}
+
+// compareVersionParts performs a basic numeric comparison of dot-separated parts.
+func compareVersionParts(a, b string) int {
 	ap := parseVersionParts(strings.TrimPrefix(a, "v"))
 	bp := parseVersionParts(strings.TrimPrefix(b, "v"))
 
@@ -224,12 +623,17 @@ func copyManifest(src *Manifest) *Manifest {
 		Name:        src.Name,
 		Version:     src.Version,
 		Description: src.Description,
+		Author:      src.Author,
+		Tags:        cloneStrings(src.Tags),
+		Extends:     src.Extends,
 		Tokens:      cloneStringMap(src.Tokens),
+		TokenTypes:  cloneStringMap(src.TokenTypes),
 		Fonts:       cloneStringMap(src.Fonts),
 		Assets: Assets{
 			Prefix: src.Assets.Prefix,
 			Files:  cloneStringMap(src.Assets.Files),
 		},
+		Preview:   src.Preview,
 		Templates: cloneStringMap(src.Templates),
 		Variants:  make(map[string]Variant, len(src.Variants)),
 	}
@@ -237,6 +641,7 @@ func copyManifest(src *Manifest) *Manifest {
 	for name, variant := range src.Variants {
 		cloned.Variants[name] = Variant{
 			Description: variant.Description,
+			Fallback:    variant.Fallback,
 			Tokens:      cloneStringMap(variant.Tokens),
 			Templates:   cloneStringMap(variant.Templates),
 			Assets: Assets{
@@ -246,5 +651,19 @@ func copyManifest(src *Manifest) *Manifest {
 		}
 	}
 
+	if src.Axes != nil {
+		cloned.Axes = make([]VariantAxis, len(src.Axes))
+		for i, axis := range src.Axes {
+			cloned.Axes[i] = VariantAxis{Name: axis.Name, Values: cloneStrings(axis.Values), Default: axis.Default}
+		}
+	}
+
 	return &cloned
 }
+
+func cloneStrings(src []string) []string {
+	if src == nil {
+		return nil
+	}
+	return append([]string(nil), src...)
+}
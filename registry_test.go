@@ -1,6 +1,10 @@
 package theme
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestRegistryRegisterAndGet(t *testing.T) {
 	reg := NewRegistry()
@@ -73,3 +77,114 @@ func TestRegisterValidatesManifest(t *testing.T) {
 		t.Fatalf("expected validation error on register")
 	}
 }
+
+func TestRegistrySubscribeEmitsEvents(t *testing.T) {
+	reg := NewRegistry()
+
+	var events []RegistryEvent
+	unsubscribe := reg.Subscribe(func(event RegistryEvent) {
+		// the registry lock must be released before delivery
+		reg.List()
+		events = append(events, event)
+	})
+
+	reg.Register(&Manifest{Name: "acme", Version: "1.0.0"})
+	reg.Register(&Manifest{Name: "acme", Version: "1.1.0"})
+	reg.Register(&Manifest{Name: "acme", Version: "1.0.0", Description: "patched"})
+
+	want := []RegistryEvent{
+		{Type: RegistryRegistered, Name: "acme", Version: "1.0.0"},
+		{Type: RegistryRegistered, Name: "acme", Version: "1.1.0", PreviousVersion: "1.0.0"},
+		{Type: RegistryReplaced, Name: "acme", Version: "1.0.0", PreviousVersion: "1.1.0"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("event %d: expected %+v, got %+v", i, want[i], events[i])
+		}
+	}
+
+	unsubscribe()
+	unsubscribe()
+	reg.Register(&Manifest{Name: "acme", Version: "2.0.0"})
+	if len(events) != len(want) {
+		t.Fatalf("expected no events after unsubscribe, got %+v", events)
+	}
+
+	if err := reg.Register(&Manifest{Name: "broken"}); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+func TestRegistryUnregister(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "acme", Version: "1.0.0"})
+	reg.Register(&Manifest{Name: "acme", Version: "1.1.0"})
+	reg.Register(&Manifest{Name: "other", Version: "1.0.0"})
+
+	var events []RegistryEvent
+	reg.Subscribe(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	if err := reg.Unregister("acme", "1.1.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if latest, _ := reg.Get("acme"); latest.Version != "1.0.0" {
+		t.Fatalf("expected 1.0.0 to become latest, got %s", latest.Version)
+	}
+	if err := reg.Unregister("acme", "1.1.0"); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound, got %v", err)
+	}
+	if err := reg.Unregister("missing", "1.0.0"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected ErrThemeNotFound, got %v", err)
+	}
+
+	if err := reg.UnregisterAll("acme"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := reg.Get("acme"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected acme to be gone, got %v", err)
+	}
+	if err := reg.UnregisterAll("acme"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected ErrThemeNotFound, got %v", err)
+	}
+	if refs := reg.List(); len(refs) != 1 || refs[0].Name != "other" {
+		t.Fatalf("expected only other to remain, got %+v", refs)
+	}
+
+	want := []RegistryEvent{
+		{Type: RegistryRemoved, Name: "acme", Version: "1.1.0", PreviousVersion: "1.1.0"},
+		{Type: RegistryRemoved, Name: "acme", Version: "1.0.0", PreviousVersion: "1.0.0"},
+	}
+	if len(events) != len(want) || events[0] != want[0] || events[1] != want[1] {
+		t.Fatalf("expected %+v, got %+v", want, events)
+	}
+}
+
+func TestRegistryRetention(t *testing.T) {
+	reg := NewRegistry(WithRetention(2))
+
+	var removed []string
+	reg.Subscribe(func(event RegistryEvent) {
+		if event.Type == RegistryRemoved {
+			removed = append(removed, event.Version)
+		}
+	})
+
+	for _, version := range []string{"1.0.0", "1.2.0", "1.1.0", "2.0.0"} {
+		if err := reg.Register(&Manifest{Name: "acme", Version: version}); err != nil {
+			t.Fatalf("register %s: %v", version, err)
+		}
+	}
+
+	refs := reg.List()
+	if len(refs) != 2 || refs[0].Version != "2.0.0" || refs[1].Version != "1.2.0" {
+		t.Fatalf("expected 2.0.0 and 1.2.0 to be retained, got %+v", refs)
+	}
+	if strings.Join(removed, ",") != "1.0.0,1.1.0" {
+		t.Fatalf("expected 1.0.0 then 1.1.0 to be pruned, got %v", removed)
+	}
+}
@@ -0,0 +1,270 @@
+package theme
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// manifestSchemaJSON is the shipped JSON Schema for theme manifests, generated from the Go structs.
+//
+//go:embed manifest.schema.json
+var manifestSchemaJSON []byte
+
+const manifestSchemaID = "https://github.com/goliatone/go-theme/manifest.schema.json"
+
+var (
+	parsedSchemaOnce sync.Once
+	parsedSchema     map[string]any
+	parsedSchemaErr  error
+)
+
+// Issue codes reported by schema validation.
+const (
+	IssueUnknownField = "unknown_field"
+	IssueInvalidType  = "invalid_type"
+)
+
+// ManifestSchema returns the JSON Schema describing Manifest, Assets and Variant documents.
+func ManifestSchema() []byte {
+	out := make([]byte, len(manifestSchemaJSON))
+	copy(out, manifestSchemaJSON)
+	return out
+}
+
+// generateManifestSchema builds the manifest JSON Schema from the struct json tags.
+func generateManifestSchema() map[string]any {
+	defs := map[string]any{}
+	root := schemaForStruct(reflect.TypeOf(Manifest{}), defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = manifestSchemaID
+	root["title"] = "Theme manifest"
+	root["$defs"] = defs
+	return root
+}
+
+func schemaForStruct(t reflect.Type, defs map[string]any) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+		properties[name] = schemaForType(field.Type, defs)
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+func schemaForType(t reflect.Type, defs map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem(), defs)}
+	case reflect.Pointer:
+		return schemaForType(t.Elem(), defs)
+	case reflect.Struct:
+		name := strings.ToLower(t.Name())
+		if _, ok := defs[name]; !ok {
+			defs[name] = map[string]any{} // reserve the name so recursive types terminate
+			defs[name] = schemaForStruct(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitempty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+func loadManifestSchema() (map[string]any, error) {
+	parsedSchemaOnce.Do(func() {
+		parsedSchemaErr = json.Unmarshal(manifestSchemaJSON, &parsedSchema)
+	})
+	return parsedSchema, parsedSchemaErr
+}
+
+// validateSchema checks a generically decoded document against the manifest schema.
+// When lenientScalars is set (YAML input), any scalar satisfies a string type.
+func validateSchema(doc any, lenientScalars bool) error {
+	schema, err := loadManifestSchema()
+	if err != nil {
+		return fmt.Errorf("load manifest schema: %w", err)
+	}
+
+	v := schemaValidator{root: schema, lenientScalars: lenientScalars}
+	v.validate(schema, normalizeSchemaDoc(doc), "")
+	if len(v.issues) == 0 {
+		return nil
+	}
+	sortValidationIssues(v.issues)
+	return ValidationError{Issues: v.issues}
+}
+
+type schemaValidator struct {
+	root           map[string]any
+	lenientScalars bool
+	issues         []ValidationIssue
+}
+
+func (v *schemaValidator) report(path, code, message string) {
+	v.issues = append(v.issues, ValidationIssue{Path: path, Code: code, Severity: SeverityError, Message: message})
+}
+
+func (v *schemaValidator) validate(schema map[string]any, value any, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		defs, _ := v.root["$defs"].(map[string]any)
+		target, _ := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		v.validate(target, value, path)
+		return
+	}
+
+	label := path
+	if label == "" {
+		label = "manifest"
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			v.report(path, IssueInvalidType, fmt.Sprintf("%s must be an object", label))
+			return
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for _, key := range sortedAnyKeys(obj) {
+			childPath := joinSchemaPath(path, key)
+			if propSchema, ok := properties[key].(map[string]any); ok {
+				v.validate(propSchema, obj[key], childPath)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					v.report(childPath, IssueUnknownField, fmt.Sprintf("%s has unknown field '%s'", label, key))
+				}
+			case map[string]any:
+				v.validate(extra, obj[key], childPath)
+			}
+		}
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				key, _ := name.(string)
+				if _, ok := obj[key]; !ok {
+					v.report(joinSchemaPath(path, key), IssueRequired, fmt.Sprintf("%s is required", joinSchemaPath(path, key)))
+				}
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			v.report(path, IssueInvalidType, fmt.Sprintf("%s must be an array", label))
+			return
+		}
+		itemSchema, _ := schema["items"].(map[string]any)
+		for i, item := range items {
+			v.validate(itemSchema, item, fmt.Sprintf("%s.%d", path, i))
+		}
+	case "string":
+		switch value.(type) {
+		case string:
+		case bool, int, int64, uint64, float64:
+			if !v.lenientScalars {
+				v.report(path, IssueInvalidType, fmt.Sprintf("%s must be a string", label))
+			}
+		default:
+			v.report(path, IssueInvalidType, fmt.Sprintf("%s must be a string", label))
+		}
+	}
+}
+
+// normalizeSchemaDoc converts YAML's map[interface{}]interface{} nodes into map[string]any.
+func normalizeSchemaDoc(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalizeSchemaDoc(item)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[fmt.Sprint(key)] = normalizeSchemaDoc(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeSchemaDoc(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedAnyKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"testing"
+)
+
+var updateSchema = flag.Bool("update-schema", false, "rewrite manifest.schema.json from the Go structs")
+
+func TestManifestSchemaInSync(t *testing.T) {
+	generated, err := json.MarshalIndent(generateManifestSchema(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal schema: %v", err)
+	}
+	generated = append(generated, '\n')
+
+	if *updateSchema {
+		if err := os.WriteFile("manifest.schema.json", generated, 0o644); err != nil {
+			t.Fatalf("write schema: %v", err)
+		}
+		return
+	}
+
+	if !bytes.Equal(generated, ManifestSchema()) {
+		t.Fatalf("manifest.schema.json is out of date; run go test -run TestManifestSchemaInSync -update-schema")
+	}
+}
+
+func TestLoadBytesSchemaValidationReportsUnknownKeys(t *testing.T) {
+	data := []byte(`
+name: default
+version: 1.0.0
+tokns:
+  primary: blue
+variants:
+  dark:
+    tokens:
+      primary: black
+    asets:
+      prefix: /dark
+`)
+
+	if _, err := LoadBytes(data, "yaml"); err != nil {
+		t.Fatalf("expected unknown keys to be ignored without schema validation, got %v", err)
+	}
+
+	_, err := LoadBytes(data, "yaml", LoadOptions{ValidateSchema: true})
+	var verr ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(verr.Issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", verr.Issues)
+	}
+	if verr.Issues[0].Path != "tokns" || verr.Issues[0].Code != IssueUnknownField {
+		t.Fatalf("expected unknown tokns field, got %+v", verr.Issues[0])
+	}
+	if verr.Issues[1].Path != "variants.dark.asets" {
+		t.Fatalf("expected unknown nested field, got %+v", verr.Issues[1])
+	}
+}
+
+func TestLoadBytesSchemaValidationJSONTypes(t *testing.T) {
+	data := []byte(`{"name": "default", "version": 1, "tokens": {"primary": ["blue"]}}`)
+
+	_, err := LoadBytes(data, "json", LoadOptions{ValidateSchema: true})
+	var verr ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(verr.Issues) != 2 || verr.Issues[0].Path != "tokens.primary" || verr.Issues[1].Path != "version" {
+		t.Fatalf("expected type issues for tokens.primary and version, got %+v", verr.Issues)
+	}
+}
+
+func TestLoadBytesSchemaValidationAcceptsValidDocument(t *testing.T) {
+	data := []byte(`{"name": "default", "version": "1.0.0", "assets": {"prefix": "/static", "files": {"logo": "logo.svg"}}}`)
+	if _, err := LoadBytes(data, "", LoadOptions{ValidateSchema: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
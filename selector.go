@@ -1,6 +1,7 @@
 package theme
 
 import (
+	"errors"
 	"fmt"
 	"path"
 	"strings"
@@ -28,24 +29,64 @@ type ThemeSelector interface {
 	Select(themeName, variant string, opts ...QueryOption) (*Selection, error)
 }
 
+// ErrVariantNotFound is returned by a strict Selector when the requested variant is not defined by the theme.
+var ErrVariantNotFound = errors.New("theme variant not found")
+
 // Selector is the default implementation of ThemeSelector using a ThemeProvider registry.
+// With StrictVariants set, Select fails with ErrVariantNotFound instead of falling back when the
+// requested variant is not defined by the resolved theme.
 type Selector struct {
 	Registry       ThemeProvider
 	DefaultTheme   string
 	DefaultVariant string
+	StrictVariants bool
 }
 
 // Selection holds the chosen theme/variant and provides resolvers for templates, assets, and tokens.
+// Theme, Variant and Version describe what is actually rendered; Requested keeps the caller's input and
+// Fallbacks explains every difference between the two. Axes holds the value picked per variant axis;
+// those variants apply on top of Variant in the manifest's axis order. RequestedAxes keeps the axis
+// values passed to SelectAxes.
 type Selection struct {
-	Theme    string
-	Variant  string
-	Manifest *Manifest
+	Theme         string
+	Variant       string
+	Axes          map[string]string
+	Version       string
+	Requested     SelectionRequest
+	RequestedAxes map[string]string
+	Fallbacks     []SelectionFallback
+	Manifest      *Manifest
+}
+
+// SelectionRequest records the theme, variant, and version (or constraint) passed to Select/SelectAxes.
+type SelectionRequest struct {
+	Theme   string
+	Variant string
+	Version string
+}
+
+// SelectionFallback explains one way the resolved selection differs from the request.
+type SelectionFallback struct {
+	Code    string
+	Message string
 }
 
+// Selection fallback codes.
+const (
+	FallbackReasonDefaultTheme    = "default_theme"
+	FallbackReasonThemeNotFound   = "theme_not_found"
+	FallbackReasonVersionNotFound = "version_not_found"
+	FallbackReasonDefaultVariant  = "default_variant"
+	FallbackReasonVariantNotFound = "variant_not_found"
+	FallbackReasonAxisNotFound    = "axis_not_found"
+)
+
 // ResolvedSelection is a complete theme snapshot with merged variant/base values.
 type ResolvedSelection struct {
 	Theme       string
 	Variant     string
+	Axes        map[string]string
+	Version     string
 	Tokens      map[string]string
 	Assets      map[string]string
 	Templates   map[string]string
@@ -56,6 +97,7 @@ type ResolvedSelection struct {
 type RendererConfig struct {
 	Theme    string
 	Variant  string
+	Axes     map[string]string
 	Partials map[string]string
 	Tokens   map[string]string
 	CSSVars  map[string]string
@@ -63,33 +105,143 @@ type RendererConfig struct {
 }
 
 // Select resolves a theme name/variant with fallback to defaults and returns a Selection.
+// A missing theme falls back to DefaultTheme; a variant the manifest does not define falls back to
+// DefaultVariant when that one exists, otherwise to the base values (or fails when StrictVariants is set).
 func (s Selector) Select(themeName, variant string, opts ...QueryOption) (*Selection, error) {
-	themeName = strings.TrimSpace(themeName)
-	if themeName == "" {
-		themeName = s.DefaultTheme
+	settings := newQueryOptions(opts)
+	requested := newSelectionRequest(themeName, settings)
+	requested.Variant = strings.TrimSpace(variant)
+
+	manifest, fallbacks, err := s.resolveTheme(requested, settings, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvedVariant, variantFallbacks, err := s.resolveVariant(manifest, requested.Variant)
+	if err != nil {
+		return nil, err
 	}
+	fallbacks = append(fallbacks, variantFallbacks...)
 
+	return &Selection{
+		Theme:     manifest.Name,
+		Variant:   resolvedVariant,
+		Version:   manifest.Version,
+		Requested: requested,
+		Fallbacks: fallbacks,
+		Manifest:  manifest,
+	}, nil
+}
+
+func newSelectionRequest(themeName string, settings queryOptions) SelectionRequest {
+	requested := SelectionRequest{Theme: strings.TrimSpace(themeName), Version: settings.version}
+	if requested.Version == "" {
+		requested.Version = settings.constraint
+	}
+	return requested
+}
+
+// resolveTheme looks up the requested theme, falling back to DefaultTheme, and reports version fallbacks.
+func (s Selector) resolveTheme(requested SelectionRequest, settings queryOptions, opts []QueryOption) (*Manifest, []SelectionFallback, error) {
 	if s.Registry == nil {
-		return nil, fmt.Errorf("theme registry is nil")
+		return nil, nil, fmt.Errorf("theme registry is nil")
+	}
+
+	var fallbacks []SelectionFallback
+	themeName := requested.Theme
+	if themeName == "" && s.DefaultTheme != "" {
+		themeName = s.DefaultTheme
+		fallbacks = append(fallbacks, SelectionFallback{
+			Code:    FallbackReasonDefaultTheme,
+			Message: fmt.Sprintf("no theme requested, using default theme '%s'", s.DefaultTheme),
+		})
 	}
 
 	manifest, err := s.Registry.Theme(themeName, opts...)
 	if err != nil && s.DefaultTheme != "" && themeName != s.DefaultTheme {
+		lookupErr := err
 		manifest, err = s.Registry.Theme(s.DefaultTheme, opts...)
+		if err == nil {
+			fallbacks = append(fallbacks, SelectionFallback{
+				Code:    FallbackReasonThemeNotFound,
+				Message: fmt.Sprintf("theme '%s' could not be resolved (%v), using default theme '%s'", themeName, lookupErr, s.DefaultTheme),
+			})
+		}
 	}
 	if err != nil {
-		return nil, fmt.Errorf("resolve theme: %w", err)
+		return nil, nil, fmt.Errorf("resolve theme: %w", err)
 	}
 
-	if variant == "" {
-		variant = s.DefaultVariant
+	if requested.Version != "" && !versionSatisfied(manifest.Version, settings) {
+		fallbacks = append(fallbacks, SelectionFallback{
+			Code:    FallbackReasonVersionNotFound,
+			Message: fmt.Sprintf("version '%s' of theme '%s' not found, using %s", requested.Version, manifest.Name, manifest.Version),
+		})
 	}
+	return manifest, fallbacks, nil
+}
 
-	return &Selection{
-		Theme:    themeName,
-		Variant:  variant,
-		Manifest: manifest,
-	}, nil
+// resolveVariant maps the requested variant onto one the manifest defines. Strict mode only rejects
+// explicitly requested variants; a DefaultVariant the theme lacks still resolves to base values.
+func (s Selector) resolveVariant(manifest *Manifest, requested string) (string, []SelectionFallback, error) {
+	var fallbacks []SelectionFallback
+	candidate := requested
+	if candidate == "" && s.DefaultVariant != "" {
+		candidate = s.DefaultVariant
+		fallbacks = append(fallbacks, SelectionFallback{
+			Code:    FallbackReasonDefaultVariant,
+			Message: fmt.Sprintf("no variant requested, using default variant '%s'", s.DefaultVariant),
+		})
+	}
+	if candidate == "" || hasVariant(manifest, candidate) {
+		return candidate, fallbacks, nil
+	}
+	if s.StrictVariants && requested != "" {
+		return "", nil, fmt.Errorf("%w: theme '%s' has no variant '%s'", ErrVariantNotFound, manifest.Name, requested)
+	}
+
+	if candidate != s.DefaultVariant && hasVariant(manifest, s.DefaultVariant) {
+		return s.DefaultVariant, append(fallbacks, SelectionFallback{
+			Code:    FallbackReasonVariantNotFound,
+			Message: fmt.Sprintf("theme '%s' has no variant '%s', using default variant '%s'", manifest.Name, candidate, s.DefaultVariant),
+		}), nil
+	}
+	return "", append(fallbacks, SelectionFallback{
+		Code:    FallbackReasonVariantNotFound,
+		Message: fmt.Sprintf("theme '%s' has no variant '%s', using base values", manifest.Name, candidate),
+	}), nil
+}
+
+func hasVariant(manifest *Manifest, name string) bool {
+	if manifest == nil || name == "" {
+		return false
+	}
+	_, ok := manifest.Variants[name]
+	return ok
+}
+
+// versionSatisfied reports whether version is what the query's WithVersion/WithConstraint asked for.
+func versionSatisfied(version string, settings queryOptions) bool {
+	if settings.version != "" {
+		return version == settings.version
+	}
+	if settings.constraint != "" {
+		constraint, err := parseConstraint(settings.constraint)
+		return err == nil && constraint.matches(version)
+	}
+	return true
+}
+
+// variants lists the selected variants, lowest precedence first: Variant, then axis values in axis order.
+func (s Selection) variants() []string {
+	var variants []string
+	if s.Variant != "" {
+		variants = append(variants, s.Variant)
+	}
+	if s.Manifest != nil {
+		variants = append(variants, s.Manifest.AxisVariants(s.Axes)...)
+	}
+	return variants
 }
 
 // Tokens returns the merged token map for the selected variant.
@@ -97,7 +249,7 @@ func (s Selection) Tokens() map[string]string {
 	if s.Manifest == nil {
 		return map[string]string{}
 	}
-	return s.Manifest.TokensForVariant(s.Variant)
+	return s.Manifest.TokensForVariants(s.variants()...)
 }
 
 // CSSVariables returns CSS vars for the variant with the provided prefix (defaults to "--" if empty).
@@ -105,12 +257,20 @@ func (s Selection) CSSVariables(prefix string) map[string]string {
 	if s.Manifest == nil {
 		return map[string]string{}
 	}
-	return s.Manifest.CSSVariables(prefix, s.Variant)
+	if prefix == "" {
+		prefix = "--"
+	}
+	tokens := s.Tokens()
+	vars := make(map[string]string, len(tokens))
+	for k, v := range tokens {
+		vars[prefix+k] = v
+	}
+	return vars
 }
 
 // Template resolves a template key using variant overrides, then base, then fallback.
 func (s Selection) Template(key, fallback string) string {
-	return resolveTemplate(s.Manifest, s.Variant, key, fallback)
+	return resolveTemplate(s.Manifest, s.variants(), key, fallback)
 }
 
 // Partials resolves a map of template keys to fallback paths.
@@ -124,7 +284,7 @@ func (s Selection) Partials(fallbacks map[string]string) map[string]string {
 
 // Asset returns a themed asset path with prefix handling (variant overrides then base). Bool indicates presence.
 func (s Selection) Asset(key string) (string, bool) {
-	return resolveAsset(s.Manifest, s.Variant, key)
+	return resolveAsset(s.Manifest, s.variants(), key)
 }
 
 // RendererTheme builds a RendererConfig given a set of fallback partials.
@@ -133,6 +293,7 @@ func (s Selection) RendererTheme(fallbacks map[string]string) RendererConfig {
 	return RendererConfig{
 		Theme:    s.Theme,
 		Variant:  s.Variant,
+		Axes:     cloneStringMap(s.Axes),
 		Partials: partials,
 		Tokens:   s.Tokens(),
 		CSSVars:  s.CSSVariables(""),
@@ -148,15 +309,18 @@ func (s Selection) Snapshot() ResolvedSelection {
 	return ResolvedSelection{
 		Theme:       s.Theme,
 		Variant:     s.Variant,
+		Axes:        cloneStringMap(s.Axes),
+		Version:     s.Version,
 		Tokens:      s.Tokens(),
-		Assets:      resolveAssets(s.Manifest, s.Variant),
-		Templates:   resolveTemplates(s.Manifest, s.Variant),
-		AssetPrefix: resolveAssetPrefix(s.Manifest, s.Variant),
+		Assets:      resolveAssets(s.Manifest, s.variants()),
+		Templates:   resolveTemplates(s.Manifest, s.variants()),
+		AssetPrefix: resolveAssetPrefix(s.Manifest, s.variants()),
 	}
 }
 
-// resolveTemplate finds the template path for a key, preferring variant overrides then base templates, falling back to the provided default.
-func resolveTemplate(manifest *Manifest, variant, key, fallback string) string {
+// resolveTemplate finds the template path for a key, preferring the variants and their fallback chains
+// (highest precedence first) then base templates, falling back to the provided default.
+func resolveTemplate(manifest *Manifest, variants []string, key, fallback string) string {
 	if manifest == nil {
 		return fallback
 	}
@@ -165,8 +329,10 @@ func resolveTemplate(manifest *Manifest, variant, key, fallback string) string {
 		return fallback
 	}
 
-	if tpl := manifestTemplate(manifest, variant, key); tpl != "" {
-		return tpl
+	for _, name := range manifest.variantLayers(variants) {
+		if tpl := manifestTemplate(manifest, name, key); tpl != "" {
+			return tpl
+		}
 	}
 	if tpl := manifestTemplate(manifest, "", key); tpl != "" {
 		return tpl
@@ -189,8 +355,9 @@ func manifestTemplate(manifest *Manifest, variant, key string) string {
 	return ""
 }
 
-// resolveAsset returns an asset path including prefix, honoring variant overrides first.
-func resolveAsset(manifest *Manifest, variant, key string) (string, bool) {
+// resolveAsset returns an asset path including prefix, honoring the variants and their fallback chains first.
+// An override uses the nearest prefix set at or below the variant that defines it, then the base prefix.
+func resolveAsset(manifest *Manifest, variants []string, key string) (string, bool) {
 	if manifest == nil {
 		return "", false
 	}
@@ -199,11 +366,12 @@ func resolveAsset(manifest *Manifest, variant, key string) (string, bool) {
 		return "", false
 	}
 
-	variantAssets := manifest.Variants[variant].Assets
-
-	if pathOverride, ok := variantAssets.Files[key]; ok && pathOverride != "" {
-		prefix := strings.TrimSuffix(activePrefix(manifest.Assets.Prefix, variantAssets.Prefix), "/")
-		return joinPath(prefix, pathOverride), true
+	layers := manifest.variantLayers(variants)
+	for i, name := range layers {
+		if pathOverride, ok := manifest.Variants[name].Assets.Files[key]; ok && pathOverride != "" {
+			prefix := strings.TrimSuffix(layerPrefix(manifest, layers[i:]), "/")
+			return joinPath(prefix, pathOverride), true
+		}
 	}
 
 	if basePath, ok := manifest.Assets.Files[key]; ok && basePath != "" {
@@ -214,7 +382,7 @@ func resolveAsset(manifest *Manifest, variant, key string) (string, bool) {
 	return "", false
 }
 
-func resolveTemplates(manifest *Manifest, variant string) map[string]string {
+func resolveTemplates(manifest *Manifest, variants []string) map[string]string {
 	templates := map[string]string{}
 	if manifest == nil {
 		return templates
@@ -224,24 +392,22 @@ func resolveTemplates(manifest *Manifest, variant string) map[string]string {
 	for key := range manifest.Templates {
 		keys[key] = struct{}{}
 	}
-	if variant != "" {
-		if v, ok := manifest.Variants[variant]; ok {
-			for key := range v.Templates {
-				keys[key] = struct{}{}
-			}
+	for _, name := range manifest.variantLayers(variants) {
+		for key := range manifest.Variants[name].Templates {
+			keys[key] = struct{}{}
 		}
 	}
 
 	for key := range keys {
 		fallback := manifest.Templates[key]
-		if tpl := resolveTemplate(manifest, variant, key, fallback); tpl != "" {
+		if tpl := resolveTemplate(manifest, variants, key, fallback); tpl != "" {
 			templates[key] = tpl
 		}
 	}
 	return templates
 }
 
-func resolveAssets(manifest *Manifest, variant string) map[string]string {
+func resolveAssets(manifest *Manifest, variants []string) map[string]string {
 	assets := map[string]string{}
 	if manifest == nil {
 		return assets
@@ -251,29 +417,32 @@ func resolveAssets(manifest *Manifest, variant string) map[string]string {
 	for key := range manifest.Assets.Files {
 		keys[key] = struct{}{}
 	}
-	if variant != "" {
-		if v, ok := manifest.Variants[variant]; ok {
-			for key := range v.Assets.Files {
-				keys[key] = struct{}{}
-			}
+	for _, name := range manifest.variantLayers(variants) {
+		for key := range manifest.Variants[name].Assets.Files {
+			keys[key] = struct{}{}
 		}
 	}
 
 	for key := range keys {
-		if resolved, ok := resolveAsset(manifest, variant, key); ok && resolved != "" {
+		if resolved, ok := resolveAsset(manifest, variants, key); ok && resolved != "" {
 			assets[key] = resolved
 		}
 	}
 	return assets
 }
 
-func resolveAssetPrefix(manifest *Manifest, variant string) string {
+func resolveAssetPrefix(manifest *Manifest, variants []string) string {
 	if manifest == nil {
 		return ""
 	}
-	if variant != "" {
-		if v, ok := manifest.Variants[variant]; ok {
-			return activePrefix(manifest.Assets.Prefix, v.Assets.Prefix)
+	return layerPrefix(manifest, manifest.variantLayers(variants))
+}
+
+// layerPrefix returns the first asset prefix set along variant layers, defaulting to the base prefix.
+func layerPrefix(manifest *Manifest, layers []string) string {
+	for _, name := range layers {
+		if prefix := manifest.Variants[name].Assets.Prefix; strings.TrimSpace(prefix) != "" {
+			return prefix
 		}
 	}
 	return manifest.Assets.Prefix
@@ -1,13 +1,18 @@
 package theme
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestSelectorSelectsWithDefaults(t *testing.T) {
 	reg := NewRegistry()
 	reg.Register(&Manifest{
-		Name:    "default",
-		Version: "1.0.0",
-		Tokens:  map[string]string{"primary": "blue"},
+		Name:     "default",
+		Version:  "1.0.0",
+		Tokens:   map[string]string{"primary": "blue"},
+		Variants: map[string]Variant{"light": {}},
 	})
 
 	selector := Selector{
@@ -201,3 +206,124 @@ func TestSelectionSnapshotPrefixFallback(t *testing.T) {
 		t.Fatalf("expected variant file to use base prefix when variant prefix missing, got %s", snapshot.Assets["badge"])
 	}
 }
+
+func TestSelectorReportsResolvedSelection(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "default", Version: "1.0.0", Variants: map[string]Variant{"light": {}}})
+	reg.Register(&Manifest{Name: "default", Version: "1.1.0", Variants: map[string]Variant{"light": {}, "dark": {}}})
+	reg.Register(&Manifest{Name: "plain", Version: "1.0.0"})
+
+	selector := Selector{Registry: reg, DefaultTheme: "default", DefaultVariant: "light"}
+
+	codes := func(sel *Selection) string {
+		var out []string
+		for _, fallback := range sel.Fallbacks {
+			out = append(out, fallback.Code)
+		}
+		return strings.Join(out, ",")
+	}
+
+	sel, err := selector.Select("missing", "dark", WithVersion("1.0.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sel.Theme != "default" || sel.Version != "1.0.0" || sel.Variant != "light" {
+		t.Fatalf("expected resolved default@1.0.0 light, got %+v", sel)
+	}
+	if sel.Requested != (SelectionRequest{Theme: "missing", Variant: "dark", Version: "1.0.0"}) {
+		t.Fatalf("expected requested values to be kept, got %+v", sel.Requested)
+	}
+	if got := codes(sel); got != "theme_not_found,variant_not_found" {
+		t.Fatalf("unexpected fallbacks: %s", got)
+	}
+
+	sel, _ = selector.Select("plain", "dark")
+	if sel.Theme != "plain" || sel.Variant != "" || codes(sel) != "variant_not_found" {
+		t.Fatalf("expected base values for an unknown variant, got %+v", sel)
+	}
+	if snapshot := sel.Snapshot(); snapshot.Variant != "" || snapshot.Version != "1.0.0" {
+		t.Fatalf("expected snapshot to report resolved values, got %+v", snapshot)
+	}
+
+	sel, _ = selector.Select("default", "dark", WithConstraint("^2"))
+	if sel.Version != "1.1.0" || sel.Variant != "dark" || codes(sel) != "version_not_found" {
+		t.Fatalf("expected version fallback to be reported, got %+v", sel)
+	}
+
+	sel, _ = selector.Select("default", "dark")
+	if len(sel.Fallbacks) != 0 {
+		t.Fatalf("expected no fallbacks for an exact match, got %+v", sel.Fallbacks)
+	}
+}
+
+func TestSelectorStrictVariants(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Manifest{Name: "default", Version: "1.0.0", Variants: map[string]Variant{"dark": {}}})
+
+	selector := Selector{Registry: reg, DefaultTheme: "default", DefaultVariant: "light", StrictVariants: true}
+
+	if _, err := selector.Select("default", "sepia"); !errors.Is(err, ErrVariantNotFound) {
+		t.Fatalf("expected ErrVariantNotFound, got %v", err)
+	}
+
+	sel, err := selector.Select("default", "dark")
+	if err != nil || sel.Variant != "dark" {
+		t.Fatalf("expected defined variant to resolve, got %+v, %v", sel, err)
+	}
+
+	sel, err = selector.Select("default", "")
+	if err != nil || sel.Variant != "" {
+		t.Fatalf("expected missing default variant to use base values, got %+v, %v", sel, err)
+	}
+}
+
+func TestSelectionVariantFallbackChain(t *testing.T) {
+	sel := Selection{
+		Theme:   "default",
+		Variant: "high-contrast-dark",
+		Manifest: &Manifest{
+			Name:      "default",
+			Version:   "1.0.0",
+			Tokens:    map[string]string{"bg": "white", "fg": "black", "border": "gray"},
+			Templates: map[string]string{"layout": "base/layout.tmpl", "header": "base/header.tmpl"},
+			Assets: Assets{
+				Prefix: "/static",
+				Files:  map[string]string{"logo": "logo.svg", "icon": "icon.svg"},
+			},
+			Variants: map[string]Variant{
+				"dark": {
+					Tokens:    map[string]string{"bg": "black", "fg": "white"},
+					Templates: map[string]string{"header": "dark/header.tmpl"},
+					Assets: Assets{
+						Prefix: "/static/dark",
+						Files:  map[string]string{"logo": "logo.svg"},
+					},
+				},
+				"high-contrast-dark": {
+					Fallback:  "dark",
+					Tokens:    map[string]string{"border": "{fg}"},
+					Templates: map[string]string{"layout": "hc/layout.tmpl"},
+					Assets:    Assets{Files: map[string]string{"icon": "hc-icon.svg"}},
+				},
+			},
+		},
+	}
+
+	if chain := sel.Manifest.VariantChain("high-contrast-dark"); strings.Join(chain, ",") != "high-contrast-dark,dark" {
+		t.Fatalf("unexpected variant chain: %v", chain)
+	}
+
+	snapshot := sel.Snapshot()
+	if snapshot.Tokens["bg"] != "black" || snapshot.Tokens["border"] != "white" {
+		t.Fatalf("expected tokens from the fallback chain, got %v", snapshot.Tokens)
+	}
+	if snapshot.Templates["layout"] != "hc/layout.tmpl" || snapshot.Templates["header"] != "dark/header.tmpl" {
+		t.Fatalf("expected templates from the fallback chain, got %v", snapshot.Templates)
+	}
+	if snapshot.Assets["logo"] != "/static/dark/logo.svg" || snapshot.Assets["icon"] != "/static/dark/hc-icon.svg" {
+		t.Fatalf("expected assets from the fallback chain, got %v", snapshot.Assets)
+	}
+	if snapshot.AssetPrefix != "/static/dark" {
+		t.Fatalf("expected prefix inherited from fallback, got %s", snapshot.AssetPrefix)
+	}
+}
@@ -0,0 +1,152 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semVersion is a parsed SemVer 2.0 version. Build metadata is kept for display only and never
+// takes part in ordering.
+type semVersion struct {
+	major, minor, patch uint64
+	prerelease          []string
+	build               string
+}
+
+// parseSemVer parses a SemVer 2.0 string; a leading "v" is accepted.
+func parseSemVer(version string) (semVersion, error) {
+	var v semVersion
+	raw := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if raw == "" {
+		return v, fmt.Errorf("version is empty")
+	}
+
+	if idx := strings.Index(raw, "+"); idx >= 0 {
+		v.build = raw[idx+1:]
+		raw = raw[:idx]
+		if err := validateSemVerIdentifiers(v.build, "build metadata", false); err != nil {
+			return v, err
+		}
+	}
+	if idx := strings.Index(raw, "-"); idx >= 0 {
+		pre := raw[idx+1:]
+		raw = raw[:idx]
+		if err := validateSemVerIdentifiers(pre, "pre-release", true); err != nil {
+			return v, err
+		}
+		v.prerelease = strings.Split(pre, ".")
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("expected major.minor.patch, got %q", raw)
+	}
+	numbers := make([]uint64, 3)
+	for i, part := range parts {
+		n, err := parseSemVerNumber(part)
+		if err != nil {
+			return v, err
+		}
+		numbers[i] = n
+	}
+	v.major, v.minor, v.patch = numbers[0], numbers[1], numbers[2]
+	return v, nil
+}
+
+func parseSemVerNumber(part string) (uint64, error) {
+	if part == "" || !isDigits(part) {
+		return 0, fmt.Errorf("%q is not a numeric version part", part)
+	}
+	if len(part) > 1 && part[0] == '0' {
+		return 0, fmt.Errorf("%q has a leading zero", part)
+	}
+	return strconv.ParseUint(part, 10, 64)
+}
+
+func validateSemVerIdentifiers(value, label string, rejectLeadingZero bool) error {
+	for _, id := range strings.Split(value, ".") {
+		if id == "" {
+			return fmt.Errorf("%s has an empty identifier", label)
+		}
+		for _, r := range id {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+				return fmt.Errorf("%s identifier %q has invalid characters", label, id)
+			}
+		}
+		if rejectLeadingZero && len(id) > 1 && id[0] == '0' && isDigits(id) {
+			return fmt.Errorf("%s identifier %q has a leading zero", label, id)
+		}
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isPrerelease reports whether v carries pre-release identifiers.
+func (v semVersion) isPrerelease() bool {
+	return len(v.prerelease) > 0
+}
+
+// compare orders versions by SemVer 2.0 precedence: core numbers, then a release above its
+// pre-releases, then pre-release identifiers left to right (numeric below alphanumeric).
+func (v semVersion) compare(o semVersion) int {
+	for _, pair := range [][2]uint64{{v.major, o.major}, {v.minor, o.minor}, {v.patch, o.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] > pair[1] {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		if cmp := comparePrereleaseIdentifier(v.prerelease[i], o.prerelease[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	switch {
+	case len(v.prerelease) > len(o.prerelease):
+		return 1
+	case len(v.prerelease) < len(o.prerelease):
+		return -1
+	}
+	return 0
+}
+
+func comparePrereleaseIdentifier(a, b string) int {
+	aNum, bNum := isDigits(a), isDigits(b)
+	switch {
+	case aNum && bNum:
+		an, _ := strconv.ParseUint(a, 10, 64)
+		bn, _ := strconv.ParseUint(b, 10, 64)
+		switch {
+		case an > bn:
+			return 1
+		case an < bn:
+			return -1
+		}
+		return 0
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompareVersionsSemVerPrecedence(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.10.0",
+		"2.0.0-beta.1",
+		"2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		if cmp := compareVersions(ordered[i], ordered[i+1]); cmp >= 0 {
+			t.Fatalf("expected %s < %s, got %d", ordered[i], ordered[i+1], cmp)
+		}
+		if cmp := compareVersions(ordered[i+1], ordered[i]); cmp <= 0 {
+			t.Fatalf("expected %s > %s, got %d", ordered[i+1], ordered[i], cmp)
+		}
+	}
+
+	if cmp := compareVersions("1.0.0+build5", "1.0.0+build6"); cmp != 0 {
+		t.Fatalf("expected build metadata to be ignored, got %d", cmp)
+	}
+	if cmp := compareVersions("v1.2.3", "1.2.3"); cmp != 0 {
+		t.Fatalf("expected v prefix to be ignored, got %d", cmp)
+	}
+}
+
+func TestParseSemVerRejectsInvalid(t *testing.T) {
+	for _, version := range []string{"1.0", "1.0.0.0", "01.0.0", "1.0.0-", "1.0.0-01", "1.0.0+", "1.0.0-beta..1", "latest"} {
+		if _, err := parseSemVer(version); err == nil {
+			t.Fatalf("expected %q to be rejected", version)
+		}
+	}
+}
+
+func TestValidateRequiresSemVer(t *testing.T) {
+	err := (&Manifest{Name: "acme", Version: "1.0"}).Validate()
+	var verr ValidationError
+	if !errors.As(err, &verr) || verr.Issues[0].Code != IssueInvalidVersion || verr.Issues[0].Path != "version" {
+		t.Fatalf("expected invalid_version issue, got %v", err)
+	}
+	if err := (&Manifest{Name: "acme", Version: "2.0.0-beta.1+sha.5114f85"}).Validate(); err != nil {
+		t.Fatalf("expected pre-release with build metadata to validate, got %v", err)
+	}
+}
+
+func TestRegistryLatestPrereleasePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		opts []RegistryOption
+		want string
+	}{
+		{nil, "2.0.0-beta.1"},
+		{[]RegistryOption{WithStableLatest()}, "1.1.0"},
+	} {
+		reg := NewRegistry(tc.opts...)
+		for _, version := range []string{"1.0.0", "1.1.0", "2.0.0-alpha", "2.0.0-beta.1"} {
+			reg.Register(&Manifest{Name: "acme", Version: version})
+		}
+		reg.Register(&Manifest{Name: "beta-only", Version: "0.1.0-rc.1"})
+
+		latest, err := reg.Get("acme")
+		if err != nil || latest.Version != tc.want {
+			t.Fatalf("expected latest %s, got %+v (%v)", tc.want, latest, err)
+		}
+		exact, err := reg.Get("acme", WithVersion("2.0.0-alpha"))
+		if err != nil || exact.Version != "2.0.0-alpha" {
+			t.Fatalf("expected exact pre-release lookup, got %+v (%v)", exact, err)
+		}
+		if only, err := reg.Get("beta-only"); err != nil || only.Version != "0.1.0-rc.1" {
+			t.Fatalf("expected pre-release-only theme to resolve, got %+v (%v)", only, err)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package theme
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SQLOption configures an SQLRegistry.
+type SQLOption func(*SQLRegistry)
+
+// WithSQLTable sets the manifests table name (default "theme_manifests"); the migrations table is
+// named after it with a "_migrations" suffix.
+func WithSQLTable(table string) SQLOption {
+	return func(r *SQLRegistry) {
+		r.table = table
+	}
+}
+
+// WithSQLPlaceholder sets how query parameters are written, e.g. func(i int) string { return fmt.Sprintf("$%d", i) }
+// for PostgreSQL. The default is "?" (SQLite, MySQL).
+func WithSQLPlaceholder(placeholder func(index int) string) SQLOption {
+	return func(r *SQLRegistry) {
+		r.placeholder = placeholder
+	}
+}
+
+// WithSQLStableLatest makes latest-version resolution skip pre-releases while a release exists, like
+// WithStableLatest for MemoryRegistry.
+func WithSQLStableLatest() SQLOption {
+	return func(r *SQLRegistry) {
+		r.stableOnly = true
+	}
+}
+
+// SQLRegistry is a Registry and ThemeProvider backed by database/sql. Manifests are stored as JSON, one
+// row per name+version; lookups follow MemoryRegistry semantics.
+type SQLRegistry struct {
+	db          *sql.DB
+	table       string
+	placeholder func(index int) string
+	stableOnly  bool
+}
+
+var sqlIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqlMigrations are applied in order and recorded in the migrations table; "%s" is the manifests table.
+var sqlMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS %s (
+	name VARCHAR(255) NOT NULL,
+	version VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL,
+	manifest TEXT NOT NULL,
+	PRIMARY KEY (name, version)
+)`,
+}
+
+// NewSQLRegistry wraps db and applies pending schema migrations.
+func NewSQLRegistry(ctx context.Context, db *sql.DB, opts ...SQLOption) (*SQLRegistry, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is nil")
+	}
+	r := &SQLRegistry{
+		db:          db,
+		table:       "theme_manifests",
+		placeholder: func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if !sqlIdentifierPattern.MatchString(r.table) {
+		return nil, fmt.Errorf("invalid table name %q", r.table)
+	}
+	if err := r.Migrate(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Migrate creates or upgrades the schema. It is safe to call repeatedly and from several processes
+// sharing a database: each migration runs in a transaction together with its record, and a migration
+// whose record another process inserted first is treated as applied.
+func (r *SQLRegistry) Migrate(ctx context.Context) error {
+	migrations := r.table + "_migrations"
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (version INTEGER NOT NULL PRIMARY KEY)`, migrations)); err != nil {
+		return fmt.Errorf("migrate theme store: %w", err)
+	}
+
+	var applied int
+	row := r.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COALESCE(MAX(version), 0) FROM %s`, migrations))
+	if err := row.Scan(&applied); err != nil {
+		return fmt.Errorf("migrate theme store: %w", err)
+	}
+
+	for i := applied; i < len(sqlMigrations); i++ {
+		if err := r.applyMigration(ctx, migrations, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs sqlMigrations[index] and records it in one transaction. When that fails because
+// a concurrent Migrate got there first (e.g. a duplicate key on the record), the recorded migration
+// is accepted instead of the error.
+func (r *SQLRegistry) applyMigration(ctx context.Context, migrations string, index int) error {
+	version := index + 1
+	err := func() error {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(sqlMigrations[index], r.table)); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (version) VALUES (%s)`, migrations, r.placeholder(1)), version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}()
+	if err == nil {
+		return nil
+	}
+
+	var recorded int
+	row := r.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE version = %s`, migrations, r.placeholder(1)), version)
+	if scanErr := row.Scan(&recorded); scanErr == nil && recorded > 0 {
+		return nil
+	}
+	return fmt.Errorf("migrate theme store to version %d: %w", version, err)
+}
+
+// Register validates and stores a manifest, replacing an existing row for the same name+version.
+func (r *SQLRegistry) Register(manifest *Manifest) error {
+	if manifest == nil {
+		return fmt.Errorf("manifest is nil")
+	}
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store manifest: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE name = %s AND version = %s`, r.table, r.placeholder(1), r.placeholder(2)),
+		manifest.Name, manifest.Version); err != nil {
+		return fmt.Errorf("store manifest: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (name, version, description, manifest) VALUES (%s, %s, %s, %s)`,
+		r.table, r.placeholder(1), r.placeholder(2), r.placeholder(3), r.placeholder(4)),
+		manifest.Name, manifest.Version, manifest.Description, string(data)); err != nil {
+		return fmt.Errorf("store manifest: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("store manifest: %w", err)
+	}
+	return nil
+}
+
+// Unregister deletes a stored version, returning ErrThemeNotFound or ErrVersionNotFound when missing.
+func (r *SQLRegistry) Unregister(name, version string) error {
+	if _, err := r.versions(name); err != nil {
+		return err
+	}
+	result, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE name = %s AND version = %s`, r.table, r.placeholder(1), r.placeholder(2)),
+		name, strings.TrimSpace(version))
+	if err != nil {
+		return fmt.Errorf("remove manifest: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, version)
+	}
+	return nil
+}
+
+// UnregisterAll deletes every stored version of a theme, returning ErrThemeNotFound when none exists.
+func (r *SQLRegistry) UnregisterAll(name string) error {
+	result, err := r.db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE name = %s`, r.table, r.placeholder(1)), name)
+	if err != nil {
+		return fmt.Errorf("remove theme: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	}
+	return nil
+}
+
+// Get loads a manifest by name with the same version, constraint and fallback handling as MemoryRegistry.
+func (r *SQLRegistry) Get(name string, opts ...QueryOption) (*Manifest, error) {
+	return getFromStore(r, name, opts, r.stableOnly)
+}
+
+// List returns every readable stored manifest, sorted like MemoryRegistry.List. All rows are fetched
+// in one query.
+func (r *SQLRegistry) List() []ManifestRef {
+	return r.list("")
+}
+
+// ListFiltered returns the page of List matching filter. NamePrefix is applied in SQL; tag, variant and
+// latest matching need the flattened manifests, so they and the pagination run on the fetched rows.
+func (r *SQLRegistry) ListFiltered(filter ListFilter) ManifestPage {
+	return filterManifestRefs(r.list(filter.NamePrefix), filter)
+}
+
+// list summarizes the stored manifests whose name starts with prefix, fetched in a single query.
+func (r *SQLRegistry) list(prefix string) []ManifestRef {
+	query := fmt.Sprintf(`SELECT name, version, manifest FROM %s`, r.table)
+	var args []any
+	if prefix != "" {
+		query += fmt.Sprintf(` WHERE SUBSTR(name, 1, %d) = %s`, utf8.RuneCountInString(prefix), r.placeholder(1))
+		args = append(args, prefix)
+	}
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	store := &sqlRowStore{registry: r, rows: map[string]map[string]string{}}
+	var names []string
+	for rows.Next() {
+		var name, version, data string
+		if err := rows.Scan(&name, &version, &data); err != nil {
+			return nil
+		}
+		if store.rows[name] == nil {
+			store.rows[name] = map[string]string{}
+			names = append(names, name)
+		}
+		store.rows[name][version] = data
+	}
+	if rows.Err() != nil {
+		return nil
+	}
+	rows.Close()
+	return listStore(store, names, r.stableOnly)
+}
+
+// Theme is an alias for Get to satisfy ThemeProvider.
+func (r *SQLRegistry) Theme(name string, opts ...QueryOption) (*Manifest, error) {
+	return r.Get(name, opts...)
+}
+
+// Themes is an alias for List to satisfy ThemeProvider.
+func (r *SQLRegistry) Themes() []ManifestRef {
+	return r.List()
+}
+
+// ThemesFiltered is an alias for ListFiltered to satisfy FilteredThemeProvider.
+func (r *SQLRegistry) ThemesFiltered(filter ListFilter) ManifestPage {
+	return r.ListFiltered(filter)
+}
+
+func (r *SQLRegistry) versions(name string) ([]string, error) {
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT version FROM %s WHERE name = %s`, r.table, r.placeholder(1)), name)
+	if err != nil {
+		return nil, fmt.Errorf("query theme versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []string
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("query theme versions: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query theme versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrThemeNotFound, name)
+	}
+	return versions, nil
+}
+
+func (r *SQLRegistry) load(name, version string) (*Manifest, error) {
+	var data string
+	row := r.db.QueryRow(fmt.Sprintf(`SELECT manifest FROM %s WHERE name = %s AND version = %s`, r.table, r.placeholder(1), r.placeholder(2)), name, version)
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, version)
+		}
+		return nil, fmt.Errorf("load manifest %s@%s: %w", name, version, err)
+	}
+	return decodeStoredManifest(name, version, data)
+}
+
+func decodeStoredManifest(name, version, data string) (*Manifest, error) {
+	manifest, err := LoadBytes([]byte(data), "json")
+	if err != nil {
+		return nil, fmt.Errorf("load manifest %s@%s: %w", name, version, err)
+	}
+	return manifest, nil
+}
+
+// sqlRowStore is a versionStore over rows fetched by one listing query. Names outside the fetched set,
+// such as extends parents excluded by a prefix, are read through the registry.
+type sqlRowStore struct {
+	registry *SQLRegistry
+	rows     map[string]map[string]string
+}
+
+func (s *sqlRowStore) versions(name string) ([]string, error) {
+	stored, ok := s.rows[name]
+	if !ok {
+		return s.registry.versions(name)
+	}
+	versions := make([]string, 0, len(stored))
+	for version := range stored {
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+func (s *sqlRowStore) load(name, version string) (*Manifest, error) {
+	stored, ok := s.rows[name]
+	if !ok {
+		return s.registry.load(name, version)
+	}
+	data, ok := stored[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s@%s", ErrVersionNotFound, name, version)
+	}
+	return decodeStoredManifest(name, version, data)
+}
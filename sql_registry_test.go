@@ -0,0 +1,79 @@
+//go:build cgo
+
+package theme
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "themes.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLRegistryContract(t *testing.T) {
+	reg, err := NewSQLRegistry(context.Background(), openTestDB(t))
+	if err != nil {
+		t.Fatalf("new sql registry: %v", err)
+	}
+	testPersistentRegistry(t, reg)
+}
+
+func TestSQLRegistryMigrationsAreIdempotent(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	reg, err := NewSQLRegistry(ctx, db, WithSQLTable("custom_themes"))
+	if err != nil {
+		t.Fatalf("new sql registry: %v", err)
+	}
+	if err := reg.Register(&Manifest{Name: "acme", Version: "1.0.0"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	reopened, err := NewSQLRegistry(ctx, db, WithSQLTable("custom_themes"))
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if refs := reopened.List(); len(refs) != 1 {
+		t.Fatalf("expected stored manifest to survive reopening, got %+v", refs)
+	}
+
+	var applied int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM custom_themes_migrations`).Scan(&applied); err != nil || applied != len(sqlMigrations) {
+		t.Fatalf("expected %d recorded migrations, got %d (%v)", len(sqlMigrations), applied, err)
+	}
+
+	if err := reopened.applyMigration(ctx, "custom_themes_migrations", 0); err != nil {
+		t.Fatalf("expected a migration recorded concurrently to be accepted, got %v", err)
+	}
+
+	if _, err := NewSQLRegistry(ctx, db, WithSQLTable("themes; DROP TABLE x")); err == nil {
+		t.Fatalf("expected invalid table name to be rejected")
+	}
+}
+
+func TestSQLRegistryStableLatest(t *testing.T) {
+	reg, err := NewSQLRegistry(context.Background(), openTestDB(t), WithSQLStableLatest())
+	if err != nil {
+		t.Fatalf("new sql registry: %v", err)
+	}
+	for _, version := range []string{"1.1.0", "2.0.0-beta.1"} {
+		if err := reg.Register(&Manifest{Name: "acme", Version: version}); err != nil {
+			t.Fatalf("register %s: %v", version, err)
+		}
+	}
+	if m, err := reg.Get("acme"); err != nil || m.Version != "1.1.0" {
+		t.Fatalf("expected latest stable 1.1.0, got %+v (%v)", m, err)
+	}
+}
@@ -0,0 +1,68 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// versionStore is the storage surface shared by the persistent registries: list the stored versions of
+// a theme and load one of them. Both return ErrThemeNotFound/ErrVersionNotFound when nothing is stored.
+type versionStore interface {
+	versions(name string) ([]string, error)
+	load(name, version string) (*Manifest, error)
+}
+
+// getFromStore applies the MemoryRegistry lookup semantics (exact versions, constraints, fallback and
+// extends flattening) to a versionStore.
+func getFromStore(store versionStore, name string, opts []QueryOption, stableOnly bool) (*Manifest, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	lookup := func(name string, settings queryOptions) (*Manifest, error) {
+		versions, err := store.versions(name)
+		if err != nil {
+			return nil, err
+		}
+		version, err := selectVersion(name, versions, settings, stableOnly)
+		if err != nil {
+			return nil, err
+		}
+		return store.load(name, version)
+	}
+
+	settings := newQueryOptions(opts)
+	manifest, err := lookup(name, settings)
+	if err != nil {
+		return nil, err
+	}
+	if settings.raw {
+		return manifest, nil
+	}
+	return flattenManifest(manifest, func(parent, version string) (*Manifest, error) {
+		return lookup(parent, parentQuery(version))
+	})
+}
+
+// listStore summarizes every readable stored version of the given theme names, flattened like Get.
+func listStore(store versionStore, names []string, stableOnly bool) []ManifestRef {
+	var refs []ManifestRef
+	for _, name := range names {
+		versions, err := store.versions(name)
+		if err != nil {
+			continue
+		}
+		for _, version := range versions {
+			manifest, err := getFromStore(store, name, []QueryOption{WithVersion(version), WithoutFallback()}, stableOnly)
+			if err != nil {
+				if manifest, err = store.load(name, version); err != nil {
+					continue
+				}
+			}
+			refs = append(refs, newManifestRef(manifest))
+		}
+	}
+	markLatest(refs, stableOnly)
+	sortManifestRefs(refs)
+	return refs
+}
@@ -0,0 +1,90 @@
+package theme
+
+import (
+	"errors"
+	"testing"
+)
+
+// testPersistentRegistry exercises the Registry contract shared by every backend.
+func testPersistentRegistry(t *testing.T, reg Registry) {
+	t.Helper()
+
+	for _, m := range []*Manifest{
+		{Name: "acme", Version: "1.0.0", Description: "first", Tokens: map[string]string{"primary": "blue"}},
+		{Name: "acme", Version: "1.2.0", Tokens: map[string]string{"primary": "indigo"}},
+		{Name: "acme", Version: "2.0.0-beta.1", Tokens: map[string]string{"primary": "teal"}},
+		{Name: "brand", Version: "1.0.0", Extends: "acme@1.0.0", Tokens: map[string]string{"accent": "red"}},
+	} {
+		if err := reg.Register(m); err != nil {
+			t.Fatalf("register %s@%s: %v", m.Name, m.Version, err)
+		}
+	}
+	if err := reg.Register(&Manifest{Name: "acme", Version: "1.0.0", Description: "patched", Tokens: map[string]string{"primary": "navy"}}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if err := reg.Register(&Manifest{Name: "acme"}); err == nil {
+		t.Fatalf("expected validation error")
+	}
+
+	latest, err := reg.Get("acme")
+	if err != nil || latest.Version != "2.0.0-beta.1" {
+		t.Fatalf("expected latest 2.0.0-beta.1, got %+v (%v)", latest, err)
+	}
+	constrained, err := reg.Get("acme", WithConstraint("^1.0"))
+	if err != nil || constrained.Version != "1.2.0" {
+		t.Fatalf("expected ^1.0 to resolve 1.2.0, got %+v (%v)", constrained, err)
+	}
+	if _, err := reg.Get("acme", WithVersion("3.0.0"), WithoutFallback()); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound, got %v", err)
+	}
+	if _, err := reg.Get("missing"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected ErrThemeNotFound, got %v", err)
+	}
+
+	brand, err := reg.Get("brand")
+	if err != nil {
+		t.Fatalf("get brand: %v", err)
+	}
+	if brand.Tokens["primary"] != "navy" || brand.Tokens["accent"] != "red" {
+		t.Fatalf("expected extends to resolve against the replaced parent, got %+v", brand.Tokens)
+	}
+
+	refs := reg.List()
+	if len(refs) != 4 || refs[0].Name != "acme" || refs[0].Version != "2.0.0-beta.1" || refs[2].Description != "patched" {
+		t.Fatalf("unexpected refs: %+v", refs)
+	}
+	lister, ok := reg.(FilteredLister)
+	if !ok {
+		t.Fatalf("expected %T to implement FilteredLister", reg)
+	}
+	if page := lister.ListFiltered(ListFilter{NamePrefix: "br"}); page.Total != 1 || page.Refs[0].Name != "brand" || page.Refs[0].TokenCount != 2 {
+		t.Fatalf("expected prefix listing to flatten parents outside the prefix, got %+v", page)
+	}
+
+	if err := reg.Unregister("acme", "2.0.0-beta.1"); err != nil {
+		t.Fatalf("unregister: %v", err)
+	}
+	if err := reg.Unregister("acme", "2.0.0-beta.1"); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected ErrVersionNotFound, got %v", err)
+	}
+	if err := reg.Unregister("missing", "1.0.0"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected ErrThemeNotFound, got %v", err)
+	}
+	if latest, _ := reg.Get("acme"); latest.Version != "1.2.0" {
+		t.Fatalf("expected 1.2.0 after unregister, got %+v", latest)
+	}
+
+	if err := reg.UnregisterAll("acme"); err != nil {
+		t.Fatalf("unregister all: %v", err)
+	}
+	if err := reg.UnregisterAll("acme"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected ErrThemeNotFound, got %v", err)
+	}
+	if _, err := reg.Get("brand"); !errors.Is(err, ErrParentNotFound) {
+		t.Fatalf("expected ErrParentNotFound once the parent is gone, got %v", err)
+	}
+}
+
+func TestMemoryRegistryContract(t *testing.T) {
+	testPersistentRegistry(t, NewRegistry())
+}
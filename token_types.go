@@ -0,0 +1,221 @@
+package theme
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TokenType names the kind of value a token holds; declared types are checked by Manifest.Validate.
+type TokenType string
+
+const (
+	TokenTypeColor       TokenType = "color"
+	TokenTypeDimension   TokenType = "dimension"
+	TokenTypeDuration    TokenType = "duration"
+	TokenTypeFontFamily  TokenType = "font-family"
+	TokenTypeFontWeight  TokenType = "font-weight"
+	TokenTypeNumber      TokenType = "number"
+	TokenTypeShadow      TokenType = "shadow"
+	TokenTypeCubicBezier TokenType = "cubic-bezier"
+)
+
+var tokenTypeValidators = map[TokenType]func(string) error{
+	TokenTypeColor:       validateColor,
+	TokenTypeDimension:   validateDimension,
+	TokenTypeDuration:    validateDuration,
+	TokenTypeFontFamily:  validateFontFamily,
+	TokenTypeFontWeight:  validateFontWeight,
+	TokenTypeNumber:      validateNumber,
+	TokenTypeShadow:      validateShadow,
+	TokenTypeCubicBezier: validateCubicBezier,
+}
+
+var (
+	hexColorPattern  = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	funcColorPattern = regexp.MustCompile(`^(?i)(rgba?|hsla?|hwb|lab|lch|oklab|oklch|color)\(.+\)$`)
+	dimensionPattern = regexp.MustCompile(`^-?(\d+(\.\d+)?|\.\d+)(px|rem|em|%|vh|vw|vmin|vmax|dvh|dvw|ch|ex|pt|pc|cm|mm|in|fr)$`)
+	durationPattern  = regexp.MustCompile(`^(\d+(\.\d+)?|\.\d+)(ms|s)$`)
+	bezierPattern    = regexp.MustCompile(`^cubic-bezier\(([^,]+),([^,]+),([^,]+),([^,]+)\)$`)
+)
+
+var namedColors = map[string]bool{}
+
+func init() {
+	for _, name := range strings.Fields(`transparent currentcolor
+		aliceblue antiquewhite aqua aquamarine azure beige bisque black blanchedalmond blue blueviolet brown
+		burlywood cadetblue chartreuse chocolate coral cornflowerblue cornsilk crimson cyan darkblue darkcyan
+		darkgoldenrod darkgray darkgreen darkgrey darkkhaki darkmagenta darkolivegreen darkorange darkorchid
+		darkred darksalmon darkseagreen darkslateblue darkslategray darkslategrey darkturquoise darkviolet
+		deeppink deepskyblue dimgray dimgrey dodgerblue firebrick floralwhite forestgreen fuchsia gainsboro
+		ghostwhite gold goldenrod gray green greenyellow grey honeydew hotpink indianred indigo ivory khaki
+		lavender lavenderblush lawngreen lemonchiffon lightblue lightcoral lightcyan lightgoldenrodyellow
+		lightgray lightgreen lightgrey lightpink lightsalmon lightseagreen lightskyblue lightslategray
+		lightslategrey lightsteelblue lightyellow lime limegreen linen magenta maroon mediumaquamarine
+		mediumblue mediumorchid mediumpurple mediumseagreen mediumslateblue mediumspringgreen mediumturquoise
+		mediumvioletred midnightblue mintcream mistyrose moccasin navajowhite navy oldlace olive olivedrab
+		orange orangered orchid palegoldenrod palegreen paleturquoise palevioletred papayawhip peachpuff peru
+		pink plum powderblue purple rebeccapurple red rosybrown royalblue saddlebrown salmon sandybrown
+		seagreen seashell sienna silver skyblue slateblue slategray slategrey snow springgreen steelblue tan
+		teal thistle tomato turquoise violet wheat white whitesmoke yellow yellowgreen`) {
+		namedColors[name] = true
+	}
+}
+
+// validateTokenTypes checks declared token types against the resolved token values.
+// Only tokens listed in declared are reported, so variant checks skip inherited base tokens.
+func validateTokenTypes(label string, types, resolved map[string]string, declared map[string]string) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, key := range sortedKeys(declared) {
+		tokenType, ok := types[key]
+		if !ok {
+			continue
+		}
+		validator, ok := tokenTypeValidators[TokenType(tokenType)]
+		if !ok {
+			continue
+		}
+		value := resolved[key]
+		if tokenReferencePattern.MatchString(value) {
+			continue
+		}
+		if err := validator(strings.TrimSpace(value)); err != nil {
+			issues = append(issues, ValidationIssue{
+				Path:     label + "." + key,
+				Code:     IssueInvalidValue,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("%s entry '%s' is not a valid %s: %v", label, key, tokenType, err),
+			})
+		}
+	}
+	return issues
+}
+
+func validateColor(value string) error {
+	if hexColorPattern.MatchString(value) || funcColorPattern.MatchString(value) || namedColors[strings.ToLower(value)] {
+		return nil
+	}
+	return fmt.Errorf("'%s' is not a hex, functional or named color", value)
+}
+
+func validateDimension(value string) error {
+	if value == "0" || dimensionPattern.MatchString(value) {
+		return nil
+	}
+	return fmt.Errorf("'%s' is not a number with a length unit", value)
+}
+
+func validateDuration(value string) error {
+	if durationPattern.MatchString(value) {
+		return nil
+	}
+	return fmt.Errorf("'%s' is not a duration in ms or s", value)
+}
+
+func validateFontFamily(value string) error {
+	for _, family := range splitTopLevel(value, ',') {
+		family = strings.TrimSpace(family)
+		if family == "" {
+			return fmt.Errorf("'%s' has an empty family name", value)
+		}
+		if quote := family[0]; quote == '"' || quote == '\'' {
+			if len(family) < 2 || family[len(family)-1] != quote {
+				return fmt.Errorf("'%s' has an unterminated quote", value)
+			}
+		}
+	}
+	return nil
+}
+
+func validateFontWeight(value string) error {
+	switch value {
+	case "normal", "bold", "bolder", "lighter":
+		return nil
+	}
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil || weight < 1 || weight > 1000 {
+		return fmt.Errorf("'%s' is not a keyword or a number between 1 and 1000", value)
+	}
+	return nil
+}
+
+func validateNumber(value string) error {
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return fmt.Errorf("'%s' is not a number", value)
+	}
+	return nil
+}
+
+func validateShadow(value string) error {
+	if value == "none" {
+		return nil
+	}
+	for _, shadow := range splitTopLevel(value, ',') {
+		lengths := 0
+		for _, part := range splitTopLevel(strings.TrimSpace(shadow), ' ') {
+			switch {
+			case part == "":
+			case part == "inset":
+			case validateDimension(part) == nil:
+				lengths++
+			case validateColor(part) == nil:
+			default:
+				return fmt.Errorf("'%s' has an unexpected part '%s'", value, part)
+			}
+		}
+		if lengths < 2 || lengths > 4 {
+			return fmt.Errorf("'%s' needs 2 to 4 lengths per shadow", value)
+		}
+	}
+	return nil
+}
+
+func validateCubicBezier(value string) error {
+	switch value {
+	case "linear", "ease", "ease-in", "ease-out", "ease-in-out":
+		return nil
+	}
+	match := bezierPattern.FindStringSubmatch(value)
+	if match == nil {
+		return fmt.Errorf("'%s' is not cubic-bezier(x1, y1, x2, y2)", value)
+	}
+	for i, raw := range match[1:] {
+		n, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("'%s' has a non-numeric control point", value)
+		}
+		if i%2 == 0 && (n < 0 || n > 1) {
+			return fmt.Errorf("'%s' has an x control point outside [0, 1]", value)
+		}
+	}
+	return nil
+}
+
+// splitTopLevel splits value on sep, ignoring separators nested in parentheses or quotes.
+func splitTopLevel(value string, sep rune) []string {
+	var (
+		parts []string
+		depth int
+		quote rune
+		start int
+	)
+	for i, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
@@ -0,0 +1,116 @@
+package theme
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTokenTypeValidators(t *testing.T) {
+	cases := []struct {
+		tokenType TokenType
+		valid     []string
+		invalid   []string
+	}{
+		{TokenTypeColor, []string{"#fff", "#0044FF", "#00000080", "rgb(0 0 0 / 50%)", "oklch(0.7 0.1 200)", "RebeccaPurple"}, []string{"#12345G", "#12", "bleu", "rgb"}},
+		{TokenTypeDimension, []string{"0", "16px", "1.5rem", "-2px", ".5em", "100%"}, []string{"16pz", "16", "px"}},
+		{TokenTypeDuration, []string{"200ms", "0.3s"}, []string{"200", "2m"}},
+		{TokenTypeFontFamily, []string{"Inter, sans-serif", `"Helvetica Neue", Arial`}, []string{"Inter,,serif", `"Helvetica`}},
+		{TokenTypeFontWeight, []string{"400", "bold"}, []string{"heavy", "0", "1200"}},
+		{TokenTypeNumber, []string{"1.5", "-3"}, []string{"one"}},
+		{TokenTypeShadow, []string{"none", "0 1px 2px #00000033", "inset 0 0 4px rgba(0, 0, 0, .2), 0 1px 1px red"}, []string{"1px", "0 1px 2px 3px 4px", "0 1px shiny"}},
+		{TokenTypeCubicBezier, []string{"ease-in", "cubic-bezier(0.4, 0, 0.2, 1)"}, []string{"cubic-bezier(2, 0, 0.2, 1)", "bouncy"}},
+	}
+
+	for _, tc := range cases {
+		validator := tokenTypeValidators[tc.tokenType]
+		for _, value := range tc.valid {
+			if err := validator(value); err != nil {
+				t.Fatalf("%s: expected %q to be valid, got %v", tc.tokenType, value, err)
+			}
+		}
+		for _, value := range tc.invalid {
+			if err := validator(value); err == nil {
+				t.Fatalf("%s: expected %q to be invalid", tc.tokenType, value)
+			}
+		}
+	}
+}
+
+func TestValidateChecksTokenTypes(t *testing.T) {
+	m := Manifest{
+		Name:    "default",
+		Version: "1.0.0",
+		Tokens: map[string]string{
+			"color.primary": "#12345G",
+			"button.bg":     "{color.primary}",
+			"space.md":      "16px",
+		},
+		TokenTypes: map[string]string{
+			"color.primary": "color",
+			"button.bg":     "color",
+			"space.md":      "dimension",
+			"space.lg":      "length",
+		},
+		Variants: map[string]Variant{
+			"compact": {Tokens: map[string]string{"space.md": "16pz"}},
+		},
+	}
+
+	err := m.Validate()
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		"tokens entry 'color.primary' is not a valid color",
+		"tokens entry 'button.bg' is not a valid color",
+		"variants.compact.tokens entry 'space.md' is not a valid dimension",
+		"token_types entry 'space.lg' has unknown type 'length'",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in %q", want, msg)
+		}
+	}
+	if strings.Contains(msg, "tokens entry 'space.md'") && !strings.Contains(msg, "variants.compact.tokens entry 'space.md'") {
+		t.Fatalf("base dimension should be valid, got %q", msg)
+	}
+}
+
+func TestDTCGTokenTypesRoundTrip(t *testing.T) {
+	manifest, err := LoadDTCG([]byte(dtcgSample), DTCGOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.TokenTypes["color.link"] != "color" || manifest.TokenTypes["easing"] != "cubic-bezier" {
+		t.Fatalf("expected inherited and explicit DTCG types, got %v", manifest.TokenTypes)
+	}
+
+	manifest.Name, manifest.Version = "imported", "1.0.0"
+	if err := manifest.Validate(); err != nil {
+		t.Fatalf("expected imported tokens to validate, got %v", err)
+	}
+
+	data, err := ExportDTCG(*manifest, "", DTCGOptions{})
+	if err != nil {
+		t.Fatalf("unexpected export error: %v", err)
+	}
+	var doc map[string]map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid export: %v", err)
+	}
+	weight := doc["font"]["weight"].(map[string]any)
+	if weight["$type"] != "fontWeight" || weight["$value"] != float64(600) {
+		t.Fatalf("expected typed numeric font weight, got %v", weight)
+	}
+
+	reloaded, err := LoadDTCG(data, DTCGOptions{})
+	if err != nil {
+		t.Fatalf("unexpected reimport error: %v", err)
+	}
+	for key, value := range manifest.Tokens {
+		if reloaded.Tokens[key] != value || reloaded.TokenTypes[key] != manifest.TokenTypes[key] {
+			t.Fatalf("round trip mismatch for %s: %q/%q vs %q/%q", key, value, manifest.TokenTypes[key], reloaded.Tokens[key], reloaded.TokenTypes[key])
+		}
+	}
+}
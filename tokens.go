@@ -0,0 +1,71 @@
+package theme
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// tokenReferencePattern matches Design Tokens style references such as "{color.primary}".
+var tokenReferencePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// tokenReferenceIssue describes a reference that could not be resolved.
+type tokenReferenceIssue struct {
+	Token     string
+	Reference string
+	Cycle     bool
+}
+
+// resolveTokenReferences replaces "{token}" references with the referenced token values.
+// References may be the whole value or embedded in it (e.g. "1px solid {color.border}").
+// Dangling references and cycles are left verbatim and reported as issues.
+func resolveTokenReferences(tokens map[string]string) (map[string]string, []tokenReferenceIssue) {
+	const (
+		unresolved = iota
+		resolving
+		resolved
+	)
+
+	out := make(map[string]string, len(tokens))
+	state := make(map[string]int, len(tokens))
+	var issues []tokenReferenceIssue
+
+	var resolve func(key string) string
+	resolve = func(key string) string {
+		if state[key] == resolved {
+			return out[key]
+		}
+		state[key] = resolving
+
+		value := tokenReferencePattern.ReplaceAllStringFunc(tokens[key], func(match string) string {
+			ref := strings.TrimSpace(match[1 : len(match)-1])
+			if _, ok := tokens[ref]; !ok {
+				issues = append(issues, tokenReferenceIssue{Token: key, Reference: ref})
+				return match
+			}
+			if state[ref] == resolving {
+				issues = append(issues, tokenReferenceIssue{Token: key, Reference: ref, Cycle: true})
+				return match
+			}
+			return resolve(ref)
+		})
+
+		state[key] = resolved
+		out[key] = value
+		return value
+	}
+
+	for _, key := range sortedKeys(tokens) {
+		resolve(key)
+	}
+	return out, issues
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
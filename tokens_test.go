@@ -0,0 +1,97 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokensForVariantResolvesReferences(t *testing.T) {
+	m := Manifest{
+		Name:    "default",
+		Version: "1.0.0",
+		Tokens: map[string]string{
+			"color.primary": "#0044ff",
+			"color.border":  "#cccccc",
+			"button.bg":     "{color.primary}",
+			"button.border": "1px solid { color.border }",
+		},
+		Variants: map[string]Variant{
+			"dark": {Tokens: map[string]string{
+				"color.primary": "#0f172a",
+				"link.color":    "{button.bg}",
+			}},
+		},
+	}
+
+	base := m.TokensForVariant("")
+	if base["button.bg"] != "#0044ff" {
+		t.Fatalf("expected alias to resolve to base value, got %s", base["button.bg"])
+	}
+	if base["button.border"] != "1px solid #cccccc" {
+		t.Fatalf("expected embedded reference to resolve, got %s", base["button.border"])
+	}
+
+	dark := m.TokensForVariant("dark")
+	if dark["button.bg"] != "#0f172a" {
+		t.Fatalf("expected base alias to follow variant override, got %s", dark["button.bg"])
+	}
+	if dark["link.color"] != "#0f172a" {
+		t.Fatalf("expected chained variant reference to resolve, got %s", dark["link.color"])
+	}
+	if m.Tokens["button.bg"] != "{color.primary}" {
+		t.Fatalf("base tokens mutated by reference resolution")
+	}
+}
+
+func TestTokensForVariantKeepsUnresolvedReferences(t *testing.T) {
+	m := Manifest{
+		Tokens: map[string]string{
+			"a":       "{b}",
+			"b":       "{a}",
+			"missing": "{nope}",
+		},
+	}
+
+	tokens := m.TokensForVariant("")
+	if tokens["missing"] != "{nope}" {
+		t.Fatalf("expected dangling reference to be kept verbatim, got %s", tokens["missing"])
+	}
+	if !strings.Contains(tokens["a"], "{") || !strings.Contains(tokens["b"], "{") {
+		t.Fatalf("expected cyclic references to stay unresolved, got %v", tokens)
+	}
+}
+
+func TestValidateReportsReferenceIssues(t *testing.T) {
+	m := Manifest{
+		Name:    "default",
+		Version: "1.0.0",
+		Tokens: map[string]string{
+			"button.bg": "{color.primary}",
+			"a":         "{b}",
+			"b":         "{a}",
+		},
+		Variants: map[string]Variant{
+			"dark": {Tokens: map[string]string{"link.color": "{color.link}"}},
+		},
+	}
+
+	err := m.Validate()
+	verr, ok := err.(ValidationError)
+	if !ok {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	msg := verr.Error()
+	for _, want := range []string{
+		"tokens entry 'button.bg' references unknown token 'color.primary'",
+		"reference cycle",
+		"variants.dark.tokens entry 'link.color' references unknown token 'color.link'",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("expected %q in %q", want, msg)
+		}
+	}
+	if strings.Count(msg, "button.bg") != 1 {
+		t.Fatalf("expected base reference issue reported once, got %q", msg)
+	}
+}
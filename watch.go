@@ -0,0 +1,251 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WatchEventType classifies a change detected by a Watcher.
+type WatchEventType string
+
+const (
+	// WatchAdded reports a manifest seen for the first time and registered.
+	WatchAdded WatchEventType = "added"
+	// WatchUpdated reports a changed manifest that was reloaded and re-registered.
+	WatchUpdated WatchEventType = "updated"
+	// WatchRemoved reports a manifest file that disappeared from the watched tree; its last good
+	// version is unregistered unless another watched file still provides it.
+	WatchRemoved WatchEventType = "removed"
+	// WatchFailed reports a manifest that could not be loaded; the last good version stays registered.
+	WatchFailed WatchEventType = "failed"
+)
+
+// WatchEvent describes a single change detected by a Watcher. Name and Version identify the registered
+// manifest (the last good one for WatchFailed/WatchRemoved); Err is set for WatchFailed and wraps the
+// DecodeError or ValidationError returned by LoadFile, or the error of a scan that could not walk the root.
+// PreviousName and PreviousVersion are set on WatchUpdated when the edit changed the manifest's name or
+// version; that entry is unregistered.
+type WatchEvent struct {
+	Type            WatchEventType
+	Path            string
+	Name            string
+	Version         string
+	PreviousName    string
+	PreviousVersion string
+	Err             error
+}
+
+// WatchOption configures a Watcher.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	interval time.Duration
+	discover []DiscoverOption
+	handler  func(WatchEvent)
+}
+
+// WithPollInterval sets how often Run rescans the tree (default one second).
+func WithPollInterval(interval time.Duration) WatchOption {
+	return func(opts *watchOptions) {
+		if interval > 0 {
+			opts.interval = interval
+		}
+	}
+}
+
+// WithWatchDiscovery applies LoadAll discovery options (depth, globs, load options) to every scan.
+func WithWatchDiscovery(opts ...DiscoverOption) WatchOption {
+	return func(settings *watchOptions) {
+		settings.discover = append(settings.discover, opts...)
+	}
+}
+
+// WithWatchHandler registers a callback invoked for every event, in scan order.
+func WithWatchHandler(handler func(WatchEvent)) WatchOption {
+	return func(opts *watchOptions) {
+		opts.handler = handler
+	}
+}
+
+// Watcher polls an fs.FS tree of manifests and keeps a Registry in sync during development.
+// Works with any fs.FS (os.DirFS, embedded or in-memory trees) by comparing file contents between scans.
+type Watcher struct {
+	fsys     fs.FS
+	root     string
+	registry Registry
+	settings watchOptions
+
+	mu    sync.Mutex
+	files map[string]watchedFile
+}
+
+type watchedFile struct {
+	hash    uint64
+	name    string
+	version string
+}
+
+// NewWatcher constructs a Watcher for the manifests below root. Nothing is loaded until Poll or Run.
+func NewWatcher(fsys fs.FS, root string, registry Registry, opts ...WatchOption) *Watcher {
+	settings := watchOptions{interval: time.Second}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+	return &Watcher{
+		fsys:     fsys,
+		root:     root,
+		registry: registry,
+		settings: settings,
+		files:    make(map[string]watchedFile),
+	}
+}
+
+// Run polls until ctx is cancelled, starting with an immediate scan, and returns ctx.Err(). A scan that
+// fails (e.g. the root is temporarily unreadable) is reported to the handler as a WatchFailed event for
+// the root and polling continues.
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.registry == nil {
+		return fmt.Errorf("theme registry is nil")
+	}
+
+	ticker := time.NewTicker(w.settings.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Poll(); err != nil && w.settings.handler != nil {
+			w.settings.handler(WatchEvent{Type: WatchFailed, Path: w.root, Err: err})
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll scans the tree once, reloads new or changed manifests through LoadFile and registers them.
+// A manifest that fails to load keeps its last good version registered and yields a WatchFailed event.
+func (w *Watcher) Poll() ([]WatchEvent, error) {
+	if w.registry == nil {
+		return nil, fmt.Errorf("theme registry is nil")
+	}
+
+	w.mu.Lock()
+	events, err := w.scan()
+	w.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if w.settings.handler != nil {
+		for _, event := range events {
+			w.settings.handler(event)
+		}
+	}
+	return events, nil
+}
+
+// scan walks the tree and diffs it against the previous scan. Callers must hold w.mu.
+func (w *Watcher) scan() ([]WatchEvent, error) {
+	settings := newDiscoverOptions(w.settings.discover)
+	seen := map[string]bool{}
+	var events []WatchEvent
+
+	err := walkManifests(w.fsys, w.root, settings, func(dir, manifestPath string, err error) {
+		if err != nil {
+			events = append(events, WatchEvent{Type: WatchFailed, Path: dir, Err: err})
+			return
+		}
+		seen[manifestPath] = true
+		if event, changed := w.reload(manifestPath, settings.loadOptions); changed {
+			events = append(events, event)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for manifestPath := range w.files {
+		if !seen[manifestPath] {
+			removed = append(removed, manifestPath)
+		}
+	}
+	sort.Strings(removed)
+	for _, manifestPath := range removed {
+		file := w.files[manifestPath]
+		delete(w.files, manifestPath)
+		event := WatchEvent{Type: WatchRemoved, Path: manifestPath, Name: file.name, Version: file.version}
+		if file.name != "" && !w.tracked(file.name, file.version) {
+			if err := w.registry.Unregister(file.name, file.version); err != nil && !errors.Is(err, ErrThemeNotFound) && !errors.Is(err, ErrVersionNotFound) {
+				event.Err = err
+			}
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// reload loads manifestPath when its content changed since the last scan. Callers must hold w.mu.
+func (w *Watcher) reload(manifestPath string, loadOptions LoadOptions) (WatchEvent, bool) {
+	data, err := fs.ReadFile(w.fsys, manifestPath)
+	if err != nil {
+		return WatchEvent{Type: WatchFailed, Path: manifestPath, Err: fmt.Errorf("read manifest: %w", err)}, true
+	}
+
+	hasher := fnv.New64a()
+	hasher.Write(data)
+	sum := hasher.Sum64()
+
+	previous, known := w.files[manifestPath]
+	if known && previous.hash == sum {
+		return WatchEvent{}, false
+	}
+
+	fail := func(err error) (WatchEvent, bool) {
+		previous.hash = sum
+		w.files[manifestPath] = previous
+		return WatchEvent{Type: WatchFailed, Path: manifestPath, Name: previous.name, Version: previous.version, Err: err}, true
+	}
+
+	manifest, err := LoadFile(w.fsys, manifestPath, loadOptions)
+	if err != nil {
+		return fail(err)
+	}
+	if err := w.registry.Register(manifest); err != nil {
+		return fail(err)
+	}
+
+	w.files[manifestPath] = watchedFile{hash: sum, name: manifest.Name, version: manifest.Version}
+	event := WatchEvent{Type: WatchUpdated, Path: manifestPath, Name: manifest.Name, Version: manifest.Version}
+	if !known || previous.name == "" {
+		event.Type = WatchAdded
+		return event, true
+	}
+
+	if previous.name != manifest.Name || previous.version != manifest.Version {
+		event.PreviousName, event.PreviousVersion = previous.name, previous.version
+		if !w.tracked(previous.name, previous.version) {
+			if err := w.registry.Unregister(previous.name, previous.version); err != nil && !errors.Is(err, ErrThemeNotFound) && !errors.Is(err, ErrVersionNotFound) {
+				event.Err = err
+			}
+		}
+	}
+	return event, true
+}
+
+// tracked reports whether another watched file still provides name@version. Callers must hold w.mu.
+func (w *Watcher) tracked(name, version string) bool {
+	for _, file := range w.files {
+		if file.name == name && file.version == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWatcherReloadsAndKeepsLastGoodVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/README.md":       &fstest.MapFile{Data: []byte("themes")},
+		"themes/acme/theme.yaml": &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\ntokens:\n  primary: blue\n")},
+	}
+	reg := NewRegistry()
+	var handled []WatchEvent
+	watcher := NewWatcher(fsys, "themes", reg, WithWatchHandler(func(event WatchEvent) {
+		handled = append(handled, event)
+	}))
+
+	events, err := watcher.Poll()
+	if err != nil {
+		t.Fatalf("unexpected poll error: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != WatchAdded || events[0].Name != "acme" {
+		t.Fatalf("expected added event, got %+v", events)
+	}
+
+	if events, _ := watcher.Poll(); len(events) != 0 {
+		t.Fatalf("expected no events for unchanged tree, got %+v", events)
+	}
+
+	fsys["themes/acme/theme.yaml"] = &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\ntokens:\n  primary: red\n")}
+	events, _ = watcher.Poll()
+	if len(events) != 1 || events[0].Type != WatchUpdated {
+		t.Fatalf("expected updated event, got %+v", events)
+	}
+	if m, _ := reg.Get("acme"); m.Tokens["primary"] != "red" {
+		t.Fatalf("expected reloaded tokens, got %+v", m.Tokens)
+	}
+
+	fsys["themes/acme/theme.yaml"] = &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\ntokens:\n  primary: \"\"\n")}
+	events, _ = watcher.Poll()
+	var validationErr ValidationError
+	if len(events) != 1 || events[0].Type != WatchFailed || !errors.As(events[0].Err, &validationErr) {
+		t.Fatalf("expected failed event with ValidationError, got %+v", events)
+	}
+	if m, _ := reg.Get("acme"); m.Tokens["primary"] != "red" {
+		t.Fatalf("expected last good version to stay registered, got %+v", m.Tokens)
+	}
+	if events, _ := watcher.Poll(); len(events) != 0 {
+		t.Fatalf("expected failure to be reported once, got %+v", events)
+	}
+
+	delete(fsys, "themes/acme/theme.yaml")
+	events, _ = watcher.Poll()
+	if len(events) != 1 || events[0].Type != WatchRemoved || events[0].Version != "1.0.0" {
+		t.Fatalf("expected removed event, got %+v", events)
+	}
+	if _, err := reg.Get("acme"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected removed manifest to be unregistered, got %v", err)
+	}
+
+	if len(handled) != 4 {
+		t.Fatalf("expected handler to see every event, got %+v", handled)
+	}
+}
+
+func TestWatcherRunStopsOnCancel(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/acme/theme.yaml": &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\n")},
+	}
+	reg := NewRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	added := make(chan struct{}, 1)
+	watcher := NewWatcher(fsys, "themes", reg, WithPollInterval(time.Millisecond), WithWatchHandler(func(event WatchEvent) {
+		if event.Type == WatchAdded {
+			added <- struct{}{}
+		}
+	}))
+
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	<-added
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := reg.Get("acme"); err != nil {
+		t.Fatalf("expected theme to be registered: %v", err)
+	}
+}
+
+func TestWatcherUnregistersRenamedManifest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/acme/theme.yaml": &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\n")},
+	}
+	reg := NewRegistry()
+	watcher := NewWatcher(fsys, "themes", reg)
+	if _, err := watcher.Poll(); err != nil {
+		t.Fatalf("unexpected poll error: %v", err)
+	}
+
+	fsys["themes/acme/theme.yaml"] = &fstest.MapFile{Data: []byte("name: acme\nversion: 1.1.0\n")}
+	events, _ := watcher.Poll()
+	if len(events) != 1 || events[0].Type != WatchUpdated || events[0].PreviousVersion != "1.0.0" || events[0].Err != nil {
+		t.Fatalf("expected updated event with previous version, got %+v", events)
+	}
+	if _, err := reg.Get("acme", WithVersion("1.0.0"), WithoutFallback()); !errors.Is(err, ErrVersionNotFound) {
+		t.Fatalf("expected previous version to be unregistered, got %v", err)
+	}
+
+	fsys["themes/acme/theme.yaml"] = &fstest.MapFile{Data: []byte("name: globex\nversion: 1.1.0\n")}
+	events, _ = watcher.Poll()
+	if len(events) != 1 || events[0].PreviousName != "acme" || events[0].Name != "globex" {
+		t.Fatalf("expected updated event with previous name, got %+v", events)
+	}
+	if _, err := reg.Get("acme"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected previous name to be unregistered, got %v", err)
+	}
+	if _, err := reg.Get("globex"); err != nil {
+		t.Fatalf("expected renamed theme to be registered: %v", err)
+	}
+}
+
+func TestWatcherRunKeepsPollingAfterScanError(t *testing.T) {
+	fsys := fstest.MapFS{}
+	reg := NewRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan WatchEvent, 16)
+	watcher := NewWatcher(fsys, "themes", reg, WithPollInterval(time.Millisecond), WithWatchHandler(func(event WatchEvent) {
+		select {
+		case events <- event:
+		default:
+		}
+	}))
+
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	event := <-events
+	if event.Type != WatchFailed || event.Path != "themes" || event.Err == nil {
+		t.Fatalf("expected failed event for the root, got %+v", event)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("expected Run to keep polling, returned %v", err)
+	case <-events:
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatcherKeepsVersionProvidedByAnotherFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"themes/README.md":            &fstest.MapFile{Data: []byte("themes")},
+		"themes/acme/theme.yaml":      &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\n")},
+		"themes/acme-copy/theme.yaml": &fstest.MapFile{Data: []byte("name: acme\nversion: 1.0.0\n")},
+	}
+	reg := NewRegistry()
+	watcher := NewWatcher(fsys, "themes", reg)
+	if _, err := watcher.Poll(); err != nil {
+		t.Fatalf("unexpected poll error: %v", err)
+	}
+
+	delete(fsys, "themes/acme-copy/theme.yaml")
+	events, _ := watcher.Poll()
+	if len(events) != 1 || events[0].Type != WatchRemoved {
+		t.Fatalf("expected removed event, got %+v", events)
+	}
+	if _, err := reg.Get("acme"); err != nil {
+		t.Fatalf("expected acme to stay registered while another file provides it: %v", err)
+	}
+
+	delete(fsys, "themes/acme/theme.yaml")
+	watcher.Poll()
+	if _, err := reg.Get("acme"); !errors.Is(err, ErrThemeNotFound) {
+		t.Fatalf("expected acme to be unregistered with its last file, got %v", err)
+	}
+}